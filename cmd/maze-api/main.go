@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -17,20 +18,61 @@ import (
 
 	"github.com/egurnov/maze-api/maze-api/app"
 	"github.com/egurnov/maze-api/maze-api/jwtservice"
+	"github.com/egurnov/maze-api/maze-api/memstore"
+	"github.com/egurnov/maze-api/maze-api/model"
 	"github.com/egurnov/maze-api/maze-api/service"
 	storepkg "github.com/egurnov/maze-api/maze-api/store"
 )
 
 type Config struct {
 	// GIN_MODE=(release|debug)
-	Port   int    `envconfig:"PORT" default:"8080"`
-	JWTKey []byte `envconfig:"JWT_SIGNING_KEY" required:"true"`
-	DBURL  string `envconfig:"DB_URL" required:"true"` // default:"root@(localhost:3306)/dreamteam"
+	Port           int    `envconfig:"PORT" default:"8080"`
+	JWTKey         []byte `envconfig:"JWT_SIGNING_KEY"`        // required to run the server
+	DBURL          string `envconfig:"DB_URL" required:"true"` // default:"root@(localhost:3306)/dreamteam", "sqlite:///var/lib/maze.db" or "memory://"
+	MigrateOnStart bool   `envconfig:"MIGRATE_ON_START" default:"false"`
+
+	SolutionCacheSize int  `envconfig:"SOLUTION_CACHE_SIZE" default:"1000"` // 0 disables the in-memory cache
+	PersistSolutions  bool `envconfig:"PERSIST_SOLUTIONS" default:"true"`
+
+	SolveWorkers int `envconfig:"SOLVE_WORKERS" default:"0"` // 0 uses all cores
+
+	SolveJobWorkers   int           `envconfig:"SOLVE_JOB_WORKERS" default:"2"`
+	SolveJobQueue     int           `envconfig:"SOLVE_JOB_QUEUE" default:"100"`
+	SolveJobTimeout   time.Duration `envconfig:"SOLVE_JOB_TIMEOUT" default:"1m"`
+	SolveJobRetention time.Duration `envconfig:"SOLVE_JOB_RETENTION" default:"1h"` // how long finished jobs are kept
 }
 
-func main() {
-	log.Println("Server starting")
+// newStores creates the stores described by the DB URL.
+// "memory://" selects a non-persistent in-memory store, other URLs are handled by storepkg.Open.
+// SQL databases must have an up-to-date schema, unless migrateOnStart is set.
+func newStores(dbURL string, migrateOnStart bool) (model.UserStore, model.MazeStore, model.SolutionStore, error) {
+	if strings.HasPrefix(dbURL, "memory://") {
+		store := memstore.New()
+		return &memstore.UserStore{Store: store}, &memstore.MazeStore{Store: store}, &memstore.SolutionStore{Store: store}, nil
+	}
+
+	store, err := storepkg.Open(dbURL)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if migrateOnStart {
+		applied, err := store.MigrateUp()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for _, m := range applied {
+			log.Infof("Applied migration %04d_%s", m.Version, m.Name)
+		}
+	}
+	if err := store.CheckSchema(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return &storepkg.UserStore{Store: store}, &storepkg.MazeStore{Store: store}, &storepkg.SolutionStore{Store: store}, nil
+}
 
+func main() {
 	// Configuration
 	var cfg Config
 	err := envconfig.Process("", &cfg)
@@ -39,8 +81,18 @@ func main() {
 	}
 	flag.Parse()
 
+	// Subcommands
+	if flag.Arg(0) == "migrate" {
+		if err := migrate(cfg.DBURL, flag.Args()[1:]); err != nil {
+			log.WithError(err).Fatal("migration failed")
+		}
+		return
+	}
+
+	log.Println("Server starting")
+
 	// Create Store
-	store, err := storepkg.NewMySQLStore(cfg.DBURL)
+	userStore, mazeStore, solutionStore, err := newStores(cfg.DBURL, cfg.MigrateOnStart)
 	if err != nil {
 		log.WithError(err).Fatal("cannot start the DB")
 	}
@@ -59,12 +111,25 @@ func main() {
 	jwtService := jwtservice.New(cfg.JWTKey, 24*time.Hour)
 
 	// Create app
+	mazeService := &service.MazeService{
+		Store: mazeStore,
+		Cache: service.NewSolutionCache(cfg.SolutionCacheSize),
+	}
+	if cfg.PersistSolutions {
+		mazeService.Solutions = solutionStore
+	}
+	jobService := service.NewSolveJobService(mazeService, cfg.SolveJobWorkers, cfg.SolveJobQueue, cfg.SolveJobTimeout, cfg.SolveJobRetention)
+	defer jobService.Close()
+
 	mazeAPI := &app.App{
 		Log:        logger,
 		JWTService: jwtService,
 
-		UserService: &service.UserService{Store: &storepkg.UserStore{Store: store}},
-		MazeService: &service.MazeService{Store: &storepkg.MazeStore{Store: store}},
+		UserService: &service.UserService{Store: userStore},
+		MazeService: mazeService,
+		JobService:  jobService,
+
+		SolveWorkers: cfg.SolveWorkers,
 	}
 
 	// Initialize DB if requested
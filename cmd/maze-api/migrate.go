@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	log "github.com/sirupsen/logrus"
+
+	storepkg "github.com/egurnov/maze-api/maze-api/store"
+)
+
+const migrateUsage = "usage: maze-api migrate up|down|status"
+
+// migrate implements the `maze-api migrate up|down|status` subcommand.
+func migrate(dbURL string, args []string) error {
+	if len(args) != 1 {
+		return errors.New(migrateUsage)
+	}
+
+	store, err := storepkg.Open(dbURL)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	switch args[0] {
+	case "up":
+		applied, err := store.MigrateUp()
+		for _, m := range applied {
+			log.Infof("Applied migration %04d_%s", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			log.Info("Schema is up to date")
+		}
+	case "down":
+		reverted, err := store.MigrateDown()
+		if err != nil {
+			return err
+		}
+		if reverted == nil {
+			log.Info("No migrations to revert")
+		} else {
+			log.Infof("Reverted migration %04d_%s", reverted.Version, reverted.Name)
+		}
+	case "status":
+		status, err := store.MigrationStatus()
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, m := range status {
+			appliedAt := "pending"
+			if m.AppliedAt != nil {
+				appliedAt = m.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", m.Version, m.Name, appliedAt)
+		}
+		return w.Flush()
+	default:
+		return errors.New(migrateUsage)
+	}
+
+	return nil
+}
@@ -88,8 +88,81 @@ const docTemplate = `{
                 "tags": [
                     "Maze"
                 ],
-                "summary": "Get all mazes belonging to the current user",
+                "summary": "Get a page of mazes belonging to the current user",
                 "operationId": "GetAllMazes",
+                "parameters": [
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Page size, 100 by default",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the nextCursor field of the previous page",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum number of rows",
+                        "name": "minRows",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of rows",
+                        "name": "maxRows",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum number of columns",
+                        "name": "minCols",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of columns",
+                        "name": "maxCols",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or after, RFC 3339",
+                        "name": "createdAfter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created before, RFC 3339",
+                        "name": "createdBefore",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "-id",
+                            "createdAt",
+                            "-createdAt",
+                            "gridSize",
+                            "-gridSize"
+                        ],
+                        "type": "string",
+                        "description": "Sort order, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "gridSize,entrance",
+                        "description": "Comma separated fields to return, all by default",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -117,8 +190,10 @@ const docTemplate = `{
                         "bearerAuth": []
                     }
                 ],
+                "description": "A text/plain body is a grid drawn as text, one line per row: the |X|_| grid printed by GET /maze/{id}/print\nor # for walls and . for open cells, the ascii print with or without labels. E marks the entrance, the entrance query parameter is used instead if there is none.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "text/plain"
                 ],
                 "produces": [
                     "application/json"
@@ -137,6 +212,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/app.MazeDTO"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Entrance of a text/plain maze without E",
+                        "name": "entrance",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -167,8 +248,46 @@ const docTemplate = `{
                 }
             }
         },
-        "/maze/{id}": {
+        "/maze/export": {
             "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "The output can be imported with POST /maze/import. Mazes are sent in batches, ordered by ID.",
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "Maze"
+                ],
+                "summary": "Stream all mazes of the current user as NDJSON, one maze per line",
+                "operationId": "ExportMazes",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/app.MazeResponseDTO"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/maze/generate": {
+            "post": {
                 "security": [
                     {
                         "bearerAuth": []
@@ -183,22 +302,24 @@ const docTemplate = `{
                 "tags": [
                     "Maze"
                 ],
-                "summary": "Get one specific maze belonging to the current user",
-                "operationId": "GetMaze",
+                "summary": "Generate a new maze and store it",
+                "operationId": "GenerateMaze",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "maze id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Generation parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/app.GenerateMazeDTO"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/app.MazeResponseDTO"
+                            "$ref": "#/definitions/app.GeneratedMazeResponseDTO"
                         }
                     },
                     "400": {
@@ -222,47 +343,56 @@ const docTemplate = `{
                 }
             }
         },
-        "/maze/{id}/print": {
-            "get": {
+        "/maze/import": {
+            "post": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
+                "description": "The body is a JSON array of mazes or NDJSON, one maze per line, up to 1000 mazes and 16 MiB. Every maze is validated on its own,\ninvalid ones are reported in items and the valid ones are stored, unless atomic=true.\nWith atomic=true nothing is stored if any maze is invalid and the response is 400 with the errors in items.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
                     "Maze"
                 ],
-                "summary": "Print one specific maze belonging to the current user",
-                "operationId": "PrintMaze",
+                "summary": "Create many mazes at once",
+                "operationId": "ImportMazes",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "maze id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Maze descriptions",
+                        "name": "mazes",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/app.MazeDTO"
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Store all mazes or none of them",
+                        "name": "atomic",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "integer"
-                            }
+                            "$ref": "#/definitions/app.ImportResponseDTO"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/app.Message"
+                            "$ref": "#/definitions/app.ImportResponseDTO"
                         }
                     },
                     "403": {
@@ -271,6 +401,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/app.Message"
                         }
                     },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -280,7 +416,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/maze/{id}/solution": {
+        "/maze/{id}": {
             "get": {
                 "security": [
                     {
@@ -296,8 +432,8 @@ const docTemplate = `{
                 "tags": [
                     "Maze"
                 ],
-                "summary": "Solve a previously stored maze",
-                "operationId": "SolveMaze",
+                "summary": "Get one specific maze belonging to the current user",
+                "operationId": "GetMaze",
                 "parameters": [
                     {
                         "type": "integer",
@@ -305,24 +441,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "enum": [
-                            "min",
-                            "max"
-                        ],
-                        "type": "string",
-                        "description": "Find shortest or longest path",
-                        "name": "steps",
-                        "in": "query",
-                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/app.SolutionResponseDTO"
+                            "$ref": "#/definitions/app.MazeResponseDTO"
                         }
                     },
                     "400": {
@@ -337,12 +462,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/app.Message"
                         }
                     },
-                    "408": {
-                        "description": "Request Timeout",
-                        "schema": {
-                            "$ref": "#/definitions/app.Message"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -350,11 +469,13 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/user": {
-            "post": {
-                "description": "Provide a unique username and password to create a new user.",
+            },
+            "put": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -362,26 +483,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Maze"
                 ],
-                "summary": "Register a new user for the API",
-                "operationId": "CreateUser",
+                "summary": "Replace one specific maze belonging to the current user",
+                "operationId": "UpdateMaze",
                 "parameters": [
                     {
-                        "description": "New user credentials",
-                        "name": "credentials",
+                        "type": "integer",
+                        "description": "maze id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Maze description",
+                        "name": "maze",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/app.CreateUserRequestDTO"
+                            "$ref": "#/definitions/app.MazeDTO"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/app.IDResponseDTO"
+                            "$ref": "#/definitions/app.MazeResponseDTO"
                         }
                     },
                     "400": {
@@ -396,6 +524,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/app.Message"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -403,40 +537,1147 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "app.CreateUserRequestDTO": {
-            "type": "object",
-            "properties": {
-                "password": {
-                    "type": "string",
-                    "minLength": 1
-                },
-                "username": {
-                    "type": "string"
-                }
-            }
-        },
-        "app.GetAllMazesResponseDTO": {
-            "type": "object",
-            "properties": {
-                "mazes": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/app.MazeResponseDTO"
+            },
+            "delete": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "Maze"
+                ],
+                "summary": "Delete one specific maze belonging to the current user",
+                "operationId": "DeleteMaze",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "maze id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
                     }
                 }
-            }
-        },
-        "app.IDResponseDTO": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "integer"
-                }
-            }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "Costs of the cells that become walls are removed. Cells match regardless of their spelling, e.g. A01 is A1.\nIf the maze is changed by another request meanwhile, nothing is changed and the response is 409.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Maze"
+                ],
+                "summary": "Add or remove walls, or move the entrance of one specific maze belonging to the current user",
+                "operationId": "PatchMaze",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "maze id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Changes to apply",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/app.PatchMazeDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/app.MazeResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/maze/{id}/analysis": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Maze"
+                ],
+                "summary": "Describe the structure and the difficulty of a previously stored maze",
+                "operationId": "GetMazeAnalysis",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "maze id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "4",
+                            "8",
+                            "8-cut",
+                            "knight"
+                        ],
+                        "type": "string",
+                        "description": "Moves of the agent, the movement of the maze by default",
+                        "name": "movement",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/app.MazeAnalysisResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/maze/{id}/print": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "The format is chosen by the format parameter or by the Accept header, text is the default.\nPlain text is the |X|_| grid. Requesting a style or a solution prints a grid with labels and marked entrance (E, ◉), exit (O, ◎) and path (*, •) instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain",
+                    "image/png",
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "Maze"
+                ],
+                "summary": "Print one specific maze belonging to the current user",
+                "operationId": "PrintMaze",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "maze id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "text",
+                            "png",
+                            "svg"
+                        ],
+                        "type": "string",
+                        "description": "Output format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "min",
+                            "max",
+                            "cheapest"
+                        ],
+                        "type": "string",
+                        "description": "Overlay the shortest, the longest or the cheapest path",
+                        "name": "solution",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ascii",
+                            "unicode"
+                        ],
+                        "type": "string",
+                        "description": "Text style, ascii by default when a solution is requested",
+                        "name": "style",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Cell size in pixels, images only. By default the largest one up to 20 that fits into 4096x4096",
+                        "name": "cellSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Draw column letters and row numbers, ignored by the |X|_| grid",
+                        "name": "labels",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/maze/{id}/solution": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "With all=true every shortest path is returned in paths, up to the limit, and total counts all of them.\nWith bestEffort=true the longest path found so far is returned when the time limit is reached, optimal is false then.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Maze"
+                ],
+                "summary": "Solve a previously stored maze",
+                "operationId": "SolveMaze",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "maze id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "min",
+                            "max",
+                            "cheapest"
+                        ],
+                        "type": "string",
+                        "description": "Find the shortest, the longest or the cheapest path",
+                        "name": "steps",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "4",
+                            "8",
+                            "8-cut",
+                            "knight"
+                        ],
+                        "type": "string",
+                        "description": "Moves of the agent, the movement of the maze by default: orthogonal, diagonal without or with cutting corners, knight jumps",
+                        "name": "movement",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return all shortest paths, steps=min only",
+                        "name": "all",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Maximum number of paths returned with all=true, 100 by default",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return the longest path found before the time limit instead of failing, steps=max only",
+                        "name": "bestEffort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "cells",
+                            "directions"
+                        ],
+                        "type": "string",
+                        "description": "With directions the path is also given as moves with repetitions, e.g. R1 D3, in directions and compass",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/app.SolutionResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/maze/{id}/solution-jobs": {
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Solution jobs"
+                ],
+                "summary": "Start solving a previously stored maze in the background",
+                "operationId": "CreateSolveJob",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "maze id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "min",
+                            "max",
+                            "cheapest"
+                        ],
+                        "type": "string",
+                        "description": "Find the shortest, the longest or the cheapest path",
+                        "name": "steps",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "4",
+                            "8",
+                            "8-cut",
+                            "knight"
+                        ],
+                        "type": "string",
+                        "description": "Moves of the agent, the movement of the maze by default",
+                        "name": "movement",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Finish with the longest path found before the time limit instead of failing, steps=max only",
+                        "name": "bestEffort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/app.SolveJobDTO"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the job"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/maze/{id}/stats": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Maze"
+                ],
+                "summary": "Count the shortest and the simple paths of a previously stored maze",
+                "operationId": "GetMazeStats",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "maze id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "4",
+                            "8",
+                            "8-cut",
+                            "knight"
+                        ],
+                        "type": "string",
+                        "description": "Moves of the agent, the movement of the maze by default",
+                        "name": "movement",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/app.MazeStatsResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/maze/{id}/verify": {
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "A valid path starts at the entrance, every step is a move allowed in the maze, it doesn't visit any cell twice\nand ends at the first exit it reaches. Every problem found is listed in errors with its position in the path.\nInstead of the cells, the path can be given as directions from the entrance in the format of GET /maze/{id}/solution?format=directions.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Maze"
+                ],
+                "summary": "Check a path submitted by a player",
+                "operationId": "VerifyPath",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "maze id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "4",
+                            "8",
+                            "8-cut",
+                            "knight"
+                        ],
+                        "type": "string",
+                        "description": "Moves of the agent, the movement of the maze by default",
+                        "name": "movement",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Cells of the path or directions from the entrance",
+                        "name": "path",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/app.VerifyPathDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/app.PathVerificationResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/solution-jobs/{jobId}": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Solution jobs"
+                ],
+                "summary": "Get status, progress and result of a solution job",
+                "operationId": "GetSolveJob",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "job id",
+                        "name": "jobId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/app.SolveJobDTO"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Solution jobs"
+                ],
+                "summary": "Cancel a queued or running solution job",
+                "operationId": "CancelSolveJob",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "job id",
+                        "name": "jobId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/app.SolveJobDTO"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/solve": {
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "With bestEffort=true the longest path found so far is returned when the time limit is reached, optimal is false then.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Maze"
+                ],
+                "summary": "Solve a maze without storing it",
+                "operationId": "SolveUnsavedMaze",
+                "parameters": [
+                    {
+                        "description": "Maze description",
+                        "name": "maze",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/app.MazeDTO"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "min",
+                            "max",
+                            "cheapest"
+                        ],
+                        "type": "string",
+                        "description": "Find the shortest, the longest or the cheapest path",
+                        "name": "steps",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "4",
+                            "8",
+                            "8-cut",
+                            "knight"
+                        ],
+                        "type": "string",
+                        "description": "Moves of the agent, the movement of the maze by default: orthogonal, diagonal without or with cutting corners, knight jumps",
+                        "name": "movement",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return the longest path found before the time limit instead of failing, steps=max only",
+                        "name": "bestEffort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "cells",
+                            "directions"
+                        ],
+                        "type": "string",
+                        "description": "With directions the path is also given as moves with repetitions, e.g. R1 D3, in directions and compass",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/app.SolutionResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "post": {
+                "description": "Provide a unique username and password to create a new user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Register a new user for the API",
+                "operationId": "CreateUser",
+                "parameters": [
+                    {
+                        "description": "New user credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/app.CreateUserRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/app.IDResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Message"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "app.CreateUserRequestDTO": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.GenerateMazeDTO": {
+            "type": "object",
+            "required": [
+                "gridSize"
+            ],
+            "properties": {
+                "algorithm": {
+                    "type": "string",
+                    "default": "backtracker",
+                    "enum": [
+                        "backtracker",
+                        "prim",
+                        "kruskal",
+                        "wilson"
+                    ]
+                },
+                "difficulty": {
+                    "type": "string",
+                    "default": "medium",
+                    "enum": [
+                        "easy",
+                        "medium",
+                        "hard"
+                    ]
+                },
+                "gridSize": {
+                    "type": "string",
+                    "example": "20x20"
+                },
+                "seed": {
+                    "type": "integer"
+                }
+            }
+        },
+        "app.GeneratedMazeResponseDTO": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "type": "string"
+                },
+                "costs": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    },
+                    "example": {
+                        "B2": 5
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "difficulty": {
+                    "type": "string"
+                },
+                "entrance": {
+                    "type": "string"
+                },
+                "exitMode": {
+                    "type": "string",
+                    "enum": [
+                        "single-exit",
+                        "any-exit",
+                        "explicit"
+                    ]
+                },
+                "exits": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "gridSize": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "movement": {
+                    "type": "string",
+                    "default": "4",
+                    "enum": [
+                        "4",
+                        "8",
+                        "8-cut",
+                        "knight"
+                    ]
+                },
+                "seed": {
+                    "type": "integer"
+                },
+                "walls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "app.GetAllMazesResponseDTO": {
+            "type": "object",
+            "properties": {
+                "mazes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/app.MazeListItemDTO"
+                    }
+                },
+                "nextCursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "app.IDResponseDTO": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "app.ImportItemDTO": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "index": {
+                    "description": "Position in the input, from 0",
+                    "type": "integer"
+                }
+            }
+        },
+        "app.ImportResponseDTO": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "imported": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/app.ImportItemDTO"
+                    }
+                }
+            }
         },
         "app.LoginCredentialsDTO": {
             "type": "object",
@@ -463,15 +1704,132 @@ const docTemplate = `{
                 }
             }
         },
+        "app.MazeAnalysisResponseDTO": {
+            "type": "object",
+            "properties": {
+                "branchingFactor": {
+                    "description": "Average number of moves from reachable cells, not counting the move back",
+                    "type": "number"
+                },
+                "chokepoints": {
+                    "description": "Cells every path goes through",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "deadEnds": {
+                    "type": "integer"
+                },
+                "difficulty": {
+                    "description": "From 0 to 100",
+                    "type": "integer"
+                },
+                "longestPathLength": {
+                    "type": "integer"
+                },
+                "longestPathOptimal": {
+                    "description": "The longest path search finished in time",
+                    "type": "boolean"
+                },
+                "reachableCells": {
+                    "description": "Open cells reachable from the entrance, exits included",
+                    "type": "integer"
+                },
+                "shortestPathLength": {
+                    "type": "integer"
+                },
+                "unreachableCells": {
+                    "type": "integer"
+                },
+                "unreachableRegions": {
+                    "type": "integer"
+                }
+            }
+        },
         "app.MazeDTO": {
             "type": "object",
             "properties": {
+                "costs": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    },
+                    "example": {
+                        "B2": 5
+                    }
+                },
+                "entrance": {
+                    "type": "string"
+                },
+                "exitMode": {
+                    "type": "string",
+                    "enum": [
+                        "single-exit",
+                        "any-exit",
+                        "explicit"
+                    ]
+                },
+                "exits": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "gridSize": {
+                    "type": "string"
+                },
+                "movement": {
+                    "type": "string",
+                    "default": "4",
+                    "enum": [
+                        "4",
+                        "8",
+                        "8-cut",
+                        "knight"
+                    ]
+                },
+                "walls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "app.MazeListItemDTO": {
+            "type": "object",
+            "properties": {
+                "costs": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
                 "entrance": {
                     "type": "string"
                 },
+                "exitMode": {
+                    "type": "string"
+                },
+                "exits": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "gridSize": {
                     "type": "string"
                 },
+                "id": {
+                    "type": "integer"
+                },
+                "movement": {
+                    "type": "string"
+                },
                 "walls": {
                     "type": "array",
                     "items": {
@@ -483,15 +1841,51 @@ const docTemplate = `{
         "app.MazeResponseDTO": {
             "type": "object",
             "properties": {
+                "costs": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    },
+                    "example": {
+                        "B2": 5
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
                 "entrance": {
                     "type": "string"
                 },
+                "exitMode": {
+                    "type": "string",
+                    "enum": [
+                        "single-exit",
+                        "any-exit",
+                        "explicit"
+                    ]
+                },
+                "exits": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "gridSize": {
                     "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
+                "movement": {
+                    "type": "string",
+                    "default": "4",
+                    "enum": [
+                        "4",
+                        "8",
+                        "8-cut",
+                        "knight"
+                    ]
+                },
                 "walls": {
                     "type": "array",
                     "items": {
@@ -500,6 +1894,22 @@ const docTemplate = `{
                 }
             }
         },
+        "app.MazeStatsResponseDTO": {
+            "type": "object",
+            "properties": {
+                "shortestPathLength": {
+                    "description": "Number of cells, 0 if there is no path",
+                    "type": "integer"
+                },
+                "shortestPaths": {
+                    "type": "integer"
+                },
+                "simplePaths": {
+                    "description": "Paths not visiting any cell twice and ending at the first exit they reach",
+                    "type": "integer"
+                }
+            }
+        },
         "app.Message": {
             "type": "object",
             "properties": {
@@ -508,14 +1918,205 @@ const docTemplate = `{
                 }
             }
         },
+        "app.PatchMazeDTO": {
+            "type": "object",
+            "properties": {
+                "addWalls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "entrance": {
+                    "type": "string"
+                },
+                "removeWalls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "app.PathErrorDTO": {
+            "type": "object",
+            "properties": {
+                "cell": {
+                    "type": "string"
+                },
+                "index": {
+                    "description": "Position in the path, from 0",
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.PathVerificationResponseDTO": {
+            "type": "object",
+            "properties": {
+                "cost": {
+                    "description": "Valid paths only",
+                    "type": "integer"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/app.PathErrorDTO"
+                    }
+                },
+                "extraSteps": {
+                    "description": "Steps more than the shortest path, valid paths only",
+                    "type": "integer"
+                },
+                "length": {
+                    "description": "Number of cells",
+                    "type": "integer"
+                },
+                "shortestPathLength": {
+                    "description": "0 if the maze has no solution",
+                    "type": "integer"
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
         "app.SolutionResponseDTO": {
             "type": "object",
             "properties": {
+                "compass": {
+                    "description": "The same with compass directions",
+                    "type": "string",
+                    "example": "E1 S3 W1 S5"
+                },
+                "cost": {
+                    "description": "Sum of the costs of the cells after the entrance",
+                    "type": "integer"
+                },
+                "directions": {
+                    "description": "Moves of the path with repetitions, format=directions only",
+                    "type": "string",
+                    "example": "R1 D3 L1 D5"
+                },
+                "elapsedMs": {
+                    "description": "Time spent solving, in milliseconds",
+                    "type": "number"
+                },
+                "exit": {
+                    "description": "The last cell of the path",
+                    "type": "string"
+                },
+                "explored": {
+                    "description": "Number of cells visited by the solver, 0 if the solution was stored",
+                    "type": "integer"
+                },
+                "optimal": {
+                    "description": "False if the best effort longest path isn't proven to be the longest one",
+                    "type": "boolean"
+                },
+                "path": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "paths": {
+                    "description": "All shortest paths up to the limit, the first one is path",
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "total": {
+                    "description": "Number of all shortest paths",
+                    "type": "integer"
+                }
+            }
+        },
+        "app.SolveJobDTO": {
+            "type": "object",
+            "properties": {
+                "bestEffort": {
+                    "type": "boolean"
+                },
+                "cost": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "exit": {
+                    "description": "The last cell of the path",
+                    "type": "string"
+                },
+                "explored": {
+                    "type": "integer"
+                },
+                "finishedAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mazeId": {
+                    "type": "integer"
+                },
+                "movement": {
+                    "type": "string"
+                },
+                "optimal": {
+                    "description": "Set when done, false if the best effort longest path isn't proven to be the longest one",
+                    "type": "boolean"
+                },
                 "path": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
+                },
+                "startedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "queued",
+                        "running",
+                        "done",
+                        "failed",
+                        "canceled"
+                    ]
+                },
+                "steps": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.VerifyPathDTO": {
+            "type": "object",
+            "properties": {
+                "directions": {
+                    "type": "string",
+                    "example": "D2"
+                },
+                "path": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "A1",
+                        "A2",
+                        "A3"
+                    ]
                 }
             }
         }
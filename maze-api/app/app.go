@@ -10,6 +10,7 @@ import (
 
 	_ "github.com/egurnov/maze-api/docs" //nolint:golint
 	"github.com/egurnov/maze-api/maze-api/model"
+	"github.com/egurnov/maze-api/maze-api/service"
 )
 
 type App struct {
@@ -18,6 +19,11 @@ type App struct {
 	JWTService  model.JWTService
 	UserService model.UserService
 	MazeService model.MazeService
+	JobService  model.SolveJobService
+
+	// SolveWorkers limits the goroutines searching for a longest path, all cores are used if not set
+	SolveWorkers int
+	solveCores   service.SolveCores // Cores used by longest path searches at the moment
 }
 
 //go:generate swag init -dir ./../../maze-api --generalInfo ./app/app.go  -o ../../docs
@@ -58,11 +64,28 @@ func (a *App) SetRoutes(r *gin.Engine) {
 		POST("/login", a.Login).
 		POST("/user", a.CreateUser)
 
+	r.POST("/solve", a.AuthorizeJWT(), a.SolveUnsavedMaze)
+
 	maze := r.Group("/maze", a.AuthorizeJWT())
 	maze.
 		POST("", a.CreateMaze).
+		POST("generate", a.GenerateMaze).
+		POST("import", a.ImportMazes).
 		GET("", a.GetAllMazes).
+		GET("export", a.ExportMazes).
 		GET(":id", a.GetMaze).
+		PUT(":id", a.UpdateMaze).
+		PATCH(":id", a.PatchMaze).
+		DELETE(":id", a.DeleteMaze).
 		GET(":id/print", a.PrintMaze).
-		GET(":id/solution", a.SolveMaze)
+		GET(":id/solution", a.SolveMaze).
+		GET(":id/stats", a.GetMazeStats).
+		GET(":id/analysis", a.GetMazeAnalysis).
+		POST(":id/verify", a.VerifyPath).
+		POST(":id/solution-jobs", a.CreateSolveJob)
+
+	jobs := r.Group("/solution-jobs", a.AuthorizeJWT())
+	jobs.
+		GET(":jobId", a.GetSolveJob).
+		DELETE(":jobId", a.CancelSolveJob)
 }
@@ -0,0 +1,149 @@
+package app
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/egurnov/maze-api/maze-api/model"
+)
+
+type SolveJobDTO struct {
+	ID         string     `json:"id"`
+	MazeID     int64      `json:"mazeId"`
+	Steps      string     `json:"steps"`
+	Movement   string     `json:"movement,omitempty"`
+	BestEffort bool       `json:"bestEffort,omitempty"`
+	Status     string     `json:"status" enums:"queued,running,done,failed,canceled"`
+	Explored   int64      `json:"explored"`
+	Path       []string   `json:"path,omitempty"`
+	Exit       string     `json:"exit,omitempty"` // The last cell of the path
+	Cost       int        `json:"cost,omitempty"`
+	Optimal    *bool      `json:"optimal,omitempty"` // Set when done, false if the best effort longest path isn't proven to be the longest one
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// CreateSolveJob godoc
+// @Summary Start solving a previously stored maze in the background
+// @ID CreateSolveJob
+// @Tags Solution jobs
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param   id  		path     integer    true  "maze id"
+// @Param   steps   query     string     true  "Find the shortest, the longest or the cheapest path"       Enums(min, max, cheapest)
+// @Param   movement  query   string     false "Moves of the agent, the movement of the maze by default"  Enums(4, 8, 8-cut, knight)
+// @Param   bestEffort  query  boolean   false "Finish with the longest path found before the time limit instead of failing, steps=max only"
+// @Success 202 {object} SolveJobDTO
+// @Header  202 {string} Location "URL of the job"
+// @Failure 400 {object} Message
+// @Failure 403 {object} Message
+// @Failure 404 {object} Message
+// @Failure 429 {object} Message
+// @Failure 500 {object} Message
+// @Router /maze/{id}/solution-jobs [post]
+func (a *App) CreateSolveJob(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 0, 64)
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+
+	opts, err := solveOptions(ctx)
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+
+	job, err := a.JobService.Submit(id, ctx.GetInt64(CTXUserID), opts)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("Location", "/solution-jobs/"+job.ID)
+	ctx.JSON(http.StatusAccepted, toSolveJobDTO(job))
+}
+
+// GetSolveJob godoc
+// @Summary Get status, progress and result of a solution job
+// @ID GetSolveJob
+// @Tags Solution jobs
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param   jobId  		path     string    true  "job id"
+// @Success 200 {object} SolveJobDTO
+// @Failure 403 {object} Message
+// @Failure 404 {object} Message
+// @Failure 500 {object} Message
+// @Router /solution-jobs/{jobId} [get]
+func (a *App) GetSolveJob(ctx *gin.Context) {
+	job, err := a.JobService.Get(ctx.Param("jobId"), ctx.GetInt64(CTXUserID))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toSolveJobDTO(job))
+}
+
+// CancelSolveJob godoc
+// @Summary Cancel a queued or running solution job
+// @ID CancelSolveJob
+// @Tags Solution jobs
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param   jobId  		path     string    true  "job id"
+// @Success 200 {object} SolveJobDTO
+// @Failure 403 {object} Message
+// @Failure 404 {object} Message
+// @Failure 500 {object} Message
+// @Router /solution-jobs/{jobId} [delete]
+func (a *App) CancelSolveJob(ctx *gin.Context) {
+	job, err := a.JobService.Cancel(ctx.Param("jobId"), ctx.GetInt64(CTXUserID))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toSolveJobDTO(job))
+}
+
+func toSolveJobDTO(j *model.SolveJob) *SolveJobDTO {
+	res := &SolveJobDTO{
+		ID:         j.ID,
+		MazeID:     j.MazeID,
+		Steps:      j.Steps,
+		Movement:   j.Movement,
+		BestEffort: j.BestEffort,
+		Status:     j.Status,
+		Explored:   j.Explored,
+		Path:       j.Path,
+		Cost:       j.Cost,
+		Error:      j.Error,
+		CreatedAt:  j.CreatedAt,
+	}
+	if len(j.Path) > 0 {
+		res.Exit = j.Path[len(j.Path)-1]
+	}
+	if j.Status == model.JobStatusDone {
+		optimal := j.Optimal
+		res.Optimal = &optimal
+	}
+	if !j.StartedAt.IsZero() {
+		startedAt := j.StartedAt
+		res.StartedAt = &startedAt
+	}
+	if !j.FinishedAt.IsZero() {
+		finishedAt := j.FinishedAt
+		res.FinishedAt = &finishedAt
+	}
+	return res
+}
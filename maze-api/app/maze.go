@@ -2,10 +2,16 @@ package app
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math/big"
 	"net/http"
+	"runtime"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -14,12 +20,39 @@ import (
 	"github.com/egurnov/maze-api/maze-api/service"
 )
 
-const SolveTimeout = 5 * time.Second
+const (
+	SolveTimeout     = 5 * time.Second
+	DefaultPageSize  = 100
+	DefaultPathLimit = 100
 
+	PathFormatCells      = "cells"
+	PathFormatDirections = "directions"
+)
+
+const (
+	contentTypeText = "text/plain"
+	contentTypePNG  = "image/png"
+	contentTypeSVG  = "image/svg+xml"
+)
+
+var printFormats = map[string]string{
+	contentTypeText: model.RenderFormatText,
+	contentTypePNG:  model.RenderFormatPNG,
+	contentTypeSVG:  model.RenderFormatSVG,
+}
+
+var mazeListFields = []string{"id", "gridSize", "entrance", "walls", "exitMode", "exits", "costs", "movement", "createdAt"}
+
+// MazeDTO describes a maze. Exits imply the explicit exit mode, single-exit is the default otherwise.
+// Costs of entering open cells are 1 unless listed. Movement is used for validation and by default for solving.
 type MazeDTO struct {
-	GridSize string   `json:"gridSize"`
-	Entrance string   `json:"entrance"`
-	Walls    []string `json:"walls"`
+	GridSize string         `json:"gridSize"`
+	Entrance string         `json:"entrance"`
+	Walls    []string       `json:"walls"`
+	ExitMode string         `json:"exitMode,omitempty" binding:"omitempty,oneof=single-exit any-exit explicit" enums:"single-exit,any-exit,explicit"`
+	Exits    []string       `json:"exits,omitempty"`
+	Costs    map[string]int `json:"costs,omitempty" example:"B2:5"`
+	Movement string         `json:"movement,omitempty" binding:"omitempty,oneof=4 8 8-cut knight" enums:"4,8,8-cut,knight" default:"4"`
 }
 
 type CreateMazeDTO = MazeDTO
@@ -27,24 +60,144 @@ type CreateMazeDTO = MazeDTO
 type MazeResponseDTO struct {
 	ID int64 `json:"id"`
 	MazeDTO
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// PatchMazeDTO describes changes to an existing maze. Walls are removed before new ones are added.
+type PatchMazeDTO struct {
+	Entrance    string   `json:"entrance,omitempty"`
+	AddWalls    []string `json:"addWalls,omitempty"`
+	RemoveWalls []string `json:"removeWalls,omitempty"`
+}
+
+// ListMazesQueryDTO holds the query parameters of GET /maze.
+type ListMazesQueryDTO struct {
+	Limit         int       `form:"limit" binding:"omitempty,min=1,max=1000"`
+	After         string    `form:"after"`
+	MinRows       int       `form:"minRows" binding:"omitempty,min=1"`
+	MaxRows       int       `form:"maxRows" binding:"omitempty,min=1"`
+	MinCols       int       `form:"minCols" binding:"omitempty,min=1"`
+	MaxCols       int       `form:"maxCols" binding:"omitempty,min=1"`
+	CreatedAfter  time.Time `form:"createdAfter" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore time.Time `form:"createdBefore" time_format:"2006-01-02T15:04:05Z07:00"`
+	Sort          string    `form:"sort" binding:"omitempty,oneof=id -id createdAt -createdAt gridSize -gridSize"`
+	Fields        string    `form:"fields"`
+}
+
+// MazeListItemDTO only contains the fields requested with the "fields" query parameter, ID is always present.
+type MazeListItemDTO struct {
+	ID        int64          `json:"id"`
+	GridSize  string         `json:"gridSize,omitempty"`
+	Entrance  string         `json:"entrance,omitempty"`
+	Walls     *[]string      `json:"walls,omitempty"`
+	ExitMode  string         `json:"exitMode,omitempty"`
+	Exits     []string       `json:"exits,omitempty"`
+	Costs     map[string]int `json:"costs,omitempty"`
+	Movement  string         `json:"movement,omitempty"`
+	CreatedAt *time.Time     `json:"createdAt,omitempty"`
 }
 
 type GetAllMazesResponseDTO struct {
-	Mazes []*MazeResponseDTO `json:"mazes"`
+	Mazes      []*MazeListItemDTO `json:"mazes"`
+	Total      int64              `json:"total"`
+	NextCursor string             `json:"nextCursor,omitempty"`
+}
+
+// GenerateMazeDTO describes a maze to generate. A random seed is used if none is given.
+type GenerateMazeDTO struct {
+	GridSize   string `json:"gridSize" binding:"required" example:"20x20"`
+	Algorithm  string `json:"algorithm" binding:"omitempty,oneof=backtracker prim kruskal wilson" enums:"backtracker,prim,kruskal,wilson" default:"backtracker"`
+	Seed       *int64 `json:"seed"`
+	Difficulty string `json:"difficulty" binding:"omitempty,oneof=easy medium hard" enums:"easy,medium,hard" default:"medium"`
+}
+
+type GeneratedMazeResponseDTO struct {
+	MazeResponseDTO
+	Algorithm  string `json:"algorithm"`
+	Seed       int64  `json:"seed"`
+	Difficulty string `json:"difficulty"`
+}
+
+// PrintMazeQueryDTO holds the query parameters of GET /maze/:id/print.
+type PrintMazeQueryDTO struct {
+	Format   string `form:"format" binding:"omitempty,oneof=text png svg"`
+	Solution string `form:"solution" binding:"omitempty,oneof=min max cheapest"`
+	CellSize int    `form:"cellSize" binding:"omitempty,min=1,max=100"`
+	Style    string `form:"style" binding:"omitempty,oneof=ascii unicode"`
+	Labels   bool   `form:"labels,default=true"`
+}
+
+type SolveMazeQueryDTO struct {
+	All   bool `form:"all"`
+	Limit int  `form:"limit" binding:"omitempty,min=1,max=1000"`
 }
 
 type SolutionResponseDTO struct {
-	Path []string `json:"path"`
+	Path  []string   `json:"path"`
+	Exit  string     `json:"exit"`                                  // The last cell of the path
+	Cost  int        `json:"cost"`                                  // Sum of the costs of the cells after the entrance
+	Paths [][]string `json:"paths,omitempty"`                       // All shortest paths up to the limit, the first one is path
+	Total *big.Int   `json:"total,omitempty" swaggertype:"integer"` // Number of all shortest paths
+
+	Optimal   bool    `json:"optimal"`   // False if the best effort longest path isn't proven to be the longest one
+	ElapsedMs float64 `json:"elapsedMs"` // Time spent solving, in milliseconds
+	Explored  int64   `json:"explored"`  // Number of cells visited by the solver, 0 if the solution was stored
+
+	Directions string `json:"directions,omitempty" example:"R1 D3 L1 D5"` // Moves of the path with repetitions, format=directions only
+	Compass    string `json:"compass,omitempty" example:"E1 S3 W1 S5"`    // The same with compass directions
+}
+
+type MazeStatsResponseDTO struct {
+	ShortestPathLength int      `json:"shortestPathLength"` // Number of cells, 0 if there is no path
+	ShortestPaths      *big.Int `json:"shortestPaths" swaggertype:"integer"`
+	SimplePaths        int64    `json:"simplePaths"` // Paths not visiting any cell twice and ending at the first exit they reach
+}
+
+type MazeAnalysisResponseDTO struct {
+	ReachableCells     int      `json:"reachableCells"` // Open cells reachable from the entrance, exits included
+	UnreachableRegions int      `json:"unreachableRegions"`
+	UnreachableCells   int      `json:"unreachableCells"`
+	DeadEnds           int      `json:"deadEnds"`
+	BranchingFactor    float64  `json:"branchingFactor"` // Average number of moves from reachable cells, not counting the move back
+	Chokepoints        []string `json:"chokepoints"`     // Cells every path goes through
+	ShortestPathLength int      `json:"shortestPathLength"`
+	LongestPathLength  int      `json:"longestPathLength"`
+	LongestPathOptimal bool     `json:"longestPathOptimal"` // The longest path search finished in time
+	Difficulty         int      `json:"difficulty"`         // From 0 to 100
+}
+
+// VerifyPathDTO has either the cells of the path or the directions from the entrance, like in solutions with format=directions.
+type VerifyPathDTO struct {
+	Path       []string `json:"path,omitempty" example:"A1,A2,A3"`
+	Directions string   `json:"directions,omitempty" example:"D2"`
+}
+
+type PathVerificationResponseDTO struct {
+	Valid              bool           `json:"valid"`
+	Length             int            `json:"length"`             // Number of cells
+	Cost               int            `json:"cost,omitempty"`     // Valid paths only
+	ShortestPathLength int            `json:"shortestPathLength"` // 0 if the maze has no solution
+	ExtraSteps         int            `json:"extraSteps"`         // Steps more than the shortest path, valid paths only
+	Errors             []PathErrorDTO `json:"errors"`
+}
+
+type PathErrorDTO struct {
+	Index   int    `json:"index"` // Position in the path, from 0
+	Cell    string `json:"cell"`
+	Message string `json:"message"`
 }
 
 // CreateMaze godoc
 // @Summary Create a new maze
+// @Description A text/plain body is a grid drawn as text, one line per row: the |X|_| grid printed by GET /maze/{id}/print
+// @Description or # for walls and . for open cells, the ascii print with or without labels. E marks the entrance, the entrance query parameter is used instead if there is none.
 // @ID CreateMaze
 // @Tags Maze
-// @Accept json
+// @Accept json,plain
 // @Produce json
 // @Security bearerAuth
 // @Param maze body MazeDTO true "Maze description"
+// @Param entrance query string false "Entrance of a text/plain maze without E"
 // @Success 201 {object} IDResponseDTO
 // @Failure 400 {object} Message
 // @Failure 403 {object} Message
@@ -52,14 +205,25 @@ type SolutionResponseDTO struct {
 // @Router /maze [post]
 func (a *App) CreateMaze(ctx *gin.Context) {
 	var maze MazeDTO
+	var err error
+
+	if ctx.ContentType() == "text/plain" {
+		maze, err = textMaze(ctx)
+	} else {
+		err = ctx.ShouldBindJSON(&maze)
+	}
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
 
-	err := ctx.ShouldBindJSON(&maze)
+	rows, cols, _, _, err := service.ValidateMaze(maze.GridSize, maze.Entrance, maze.Walls, maze.ExitMode, maze.Exits, maze.Movement)
 	if err != nil {
 		ctx.Error(err).SetType(BadRequestErrorType)
 		return
 	}
 
-	rows, cols, _, _, err := service.ValidateMaze(maze.GridSize, maze.Entrance, maze.Walls)
+	err = service.ValidateCosts(rows, cols, maze.Walls, maze.Costs)
 	if err != nil {
 		ctx.Error(err).SetType(BadRequestErrorType)
 		return
@@ -70,6 +234,10 @@ func (a *App) CreateMaze(ctx *gin.Context) {
 		Cols:     cols,
 		Entrance: maze.Entrance,
 		Walls:    maze.Walls,
+		ExitMode: maze.exitMode(),
+		Exits:    maze.Exits,
+		Costs:    maze.Costs,
+		Movement: movementOf(maze.Movement),
 		UserID:   ctx.GetInt64(CTXUserID),
 	})
 	if err != nil {
@@ -80,6 +248,102 @@ func (a *App) CreateMaze(ctx *gin.Context) {
 	ctx.JSON(http.StatusCreated, IDResponseDTO{ID: id})
 }
 
+// textMaze reads a maze drawn as text from the body.
+func textMaze(ctx *gin.Context) (MazeDTO, error) {
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return MazeDTO{}, err
+	}
+
+	rows, cols, entrance, walls, err := service.ParseTextMaze(string(body))
+	if err != nil {
+		return MazeDTO{}, err
+	}
+
+	if param := ctx.Query("entrance"); param != "" {
+		if entrance != "" {
+			return MazeDTO{}, errors.New("the entrance is marked with E and given as a parameter")
+		}
+		entrance = param
+	}
+	if entrance == "" {
+		return MazeDTO{}, errors.New("no entrance, mark it with E or pass the entrance parameter")
+	}
+
+	return MazeDTO{GridSize: gridSize(rows, cols), Entrance: entrance, Walls: walls}, nil
+}
+
+// GenerateMaze godoc
+// @Summary Generate a new maze and store it
+// @ID GenerateMaze
+// @Tags Maze
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param params body GenerateMazeDTO true "Generation parameters"
+// @Success 201 {object} GeneratedMazeResponseDTO
+// @Failure 400 {object} Message
+// @Failure 403 {object} Message
+// @Failure 500 {object} Message
+// @Router /maze/generate [post]
+func (a *App) GenerateMaze(ctx *gin.Context) {
+	var params GenerateMazeDTO
+
+	err := ctx.ShouldBindJSON(&params)
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+
+	opts := service.GenerateOptions{
+		Algorithm:  params.Algorithm,
+		Seed:       time.Now().UnixNano(),
+		Difficulty: params.Difficulty,
+	}
+	if opts.Algorithm == "" {
+		opts.Algorithm = service.AlgorithmBacktracker
+	}
+	if opts.Difficulty == "" {
+		opts.Difficulty = service.DifficultyMedium
+	}
+	if params.Seed != nil {
+		opts.Seed = *params.Seed
+	}
+
+	rows, cols, entrance, walls, err := service.GenerateMaze(params.GridSize, opts)
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+
+	id, err := a.MazeService.Create(&model.Maze{
+		Rows:     rows,
+		Cols:     cols,
+		Entrance: entrance,
+		Walls:    walls,
+		ExitMode: model.ExitModeSingle,
+		Movement: model.MovementOrthogonal,
+		UserID:   ctx.GetInt64(CTXUserID),
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	res, err := a.MazeService.GetByID(id, ctx.GetInt64(CTXUserID))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, &GeneratedMazeResponseDTO{
+		MazeResponseDTO: *toMazeResponseDTO(res),
+		Algorithm:       opts.Algorithm,
+		Seed:            opts.Seed,
+		Difficulty:      opts.Difficulty,
+	})
+}
+
 // GetMaze godoc
 // @Summary Get one specific maze belonging to the current user
 // @ID GetMaze
@@ -106,27 +370,199 @@ func (a *App) GetMaze(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, &MazeResponseDTO{
-		ID: res.ID,
-		MazeDTO: MazeDTO{
-			GridSize: fmt.Sprintf("%dx%d", res.Rows, res.Cols),
-			Entrance: res.Entrance,
-			Walls:    res.Walls,
-		},
-	})
+	ctx.JSON(http.StatusOK, toMazeResponseDTO(res))
+}
+
+// UpdateMaze godoc
+// @Summary Replace one specific maze belonging to the current user
+// @ID UpdateMaze
+// @Tags Maze
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param   id  path     integer     true  "maze id"
+// @Param maze body MazeDTO true "Maze description"
+// @Success 200 {object} MazeResponseDTO
+// @Failure 400 {object} Message
+// @Failure 403 {object} Message
+// @Failure 404 {object} Message
+// @Failure 500 {object} Message
+// @Router /maze/{id} [put]
+func (a *App) UpdateMaze(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 0, 64)
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+
+	var maze MazeDTO
+	err = ctx.ShouldBindJSON(&maze)
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+
+	rows, cols, _, _, err := service.ValidateMaze(maze.GridSize, maze.Entrance, maze.Walls, maze.ExitMode, maze.Exits, maze.Movement)
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+
+	err = service.ValidateCosts(rows, cols, maze.Walls, maze.Costs)
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+
+	res := &model.Maze{
+		ID:       id,
+		Rows:     rows,
+		Cols:     cols,
+		Entrance: maze.Entrance,
+		Walls:    maze.Walls,
+		ExitMode: maze.exitMode(),
+		Exits:    maze.Exits,
+		Costs:    maze.Costs,
+		Movement: movementOf(maze.Movement),
+		UserID:   ctx.GetInt64(CTXUserID),
+	}
+	err = a.MazeService.Update(res)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	res, err = a.MazeService.GetByID(id, ctx.GetInt64(CTXUserID))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toMazeResponseDTO(res))
+}
+
+// PatchMaze godoc
+// @Summary Add or remove walls, or move the entrance of one specific maze belonging to the current user
+// @Description Costs of the cells that become walls are removed. Cells match regardless of their spelling, e.g. A01 is A1.
+// @Description If the maze is changed by another request meanwhile, nothing is changed and the response is 409.
+// @ID PatchMaze
+// @Tags Maze
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param   id  path     integer     true  "maze id"
+// @Param patch body PatchMazeDTO true "Changes to apply"
+// @Success 200 {object} MazeResponseDTO
+// @Failure 400 {object} Message
+// @Failure 403 {object} Message
+// @Failure 404 {object} Message
+// @Failure 409 {object} Message
+// @Failure 500 {object} Message
+// @Router /maze/{id} [patch]
+func (a *App) PatchMaze(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 0, 64)
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+
+	var patch PatchMazeDTO
+	err = ctx.ShouldBindJSON(&patch)
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+
+	res, err := a.MazeService.GetByID(id, ctx.GetInt64(CTXUserID))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if patch.Entrance != "" {
+		res.Entrance = patch.Entrance
+	}
+	res.Walls = patchWalls(res.Walls, patch.AddWalls, patch.RemoveWalls)
+	// Costs can't be patched, a new wall replaces the cost of its cell instead of failing validation
+	added := make(map[string]bool, len(patch.AddWalls))
+	for _, wall := range patch.AddWalls {
+		added[canonicalCell(wall)] = true
+	}
+	for cell := range res.Costs {
+		if added[canonicalCell(cell)] {
+			delete(res.Costs, cell)
+		}
+	}
+
+	_, _, _, _, err = service.ValidateMaze(gridSize(res.Rows, res.Cols), res.Entrance, res.Walls, res.ExitMode, res.Exits, res.Movement)
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+
+	err = service.ValidateCosts(res.Rows, res.Cols, res.Walls, res.Costs)
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+
+	// res.Version is the version that was read, a concurrent change of the maze fails the update
+	err = a.MazeService.Update(res)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toMazeResponseDTO(res))
+}
+
+// DeleteMaze godoc
+// @Summary Delete one specific maze belonging to the current user
+// @ID DeleteMaze
+// @Tags Maze
+// @Security bearerAuth
+// @Param   id  path     integer     true  "maze id"
+// @Success 204
+// @Failure 400 {object} Message
+// @Failure 403 {object} Message
+// @Failure 404 {object} Message
+// @Failure 500 {object} Message
+// @Router /maze/{id} [delete]
+func (a *App) DeleteMaze(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 0, 64)
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+
+	err = a.MazeService.Delete(id, ctx.GetInt64(CTXUserID))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
 }
 
 // PrintMaze godoc
 // @Summary Print one specific maze belonging to the current user
+// @Description The format is chosen by the format parameter or by the Accept header, text is the default.
+// @Description Plain text is the |X|_| grid. Requesting a style or a solution prints a grid with labels and marked entrance (E, ◉), exit (O, ◎) and path (*, •) instead.
 // @ID PrintMaze
 // @Tags Maze
 // @Accept json
-// @Produce plain
+// @Produce plain,png,image/svg+xml
 // @Security bearerAuth
-// @Param   id  path     integer     true  "maze id"
+// @Param   id        path   integer  true   "maze id"
+// @Param   format    query  string   false  "Output format, overrides the Accept header"  Enums(text, png, svg)
+// @Param   solution  query  string   false  "Overlay the shortest, the longest or the cheapest path"  Enums(min, max, cheapest)
+// @Param   style     query  string   false  "Text style, ascii by default when a solution is requested"  Enums(ascii, unicode)
+// @Param   cellSize  query  integer  false  "Cell size in pixels, images only. By default the largest one up to 20 that fits into 4096x4096"  minimum(1) maximum(100)
+// @Param   labels    query  boolean  false  "Draw column letters and row numbers, ignored by the |X|_| grid"  default(true)
 // @Success 200 {array} byte
 // @Failure 400 {object} Message
 // @Failure 403 {object} Message
+// @Failure 408 {object} Message
 // @Failure 500 {object} Message
 // @Router /maze/{id}/print [get]
 func (a *App) PrintMaze(ctx *gin.Context) {
@@ -136,58 +572,158 @@ func (a *App) PrintMaze(ctx *gin.Context) {
 		return
 	}
 
-	res, err := a.MazeService.PrintMaze(id, ctx.GetInt64(CTXUserID))
+	var query PrintMazeQueryDTO
+	err = ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+
+	format := query.Format
+	if format == "" {
+		format = printFormats[ctx.NegotiateFormat(contentTypeText, contentTypePNG, contentTypeSVG)]
+	}
+
+	if format == "" {
+		format = model.RenderFormatText
+	}
+
+	// Plain |X|_| grid
+	if format == model.RenderFormatText && query.Solution == "" && query.Style == "" {
+		res, err := a.MazeService.PrintMaze(id, ctx.GetInt64(CTXUserID))
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+
+		ctx.Data(http.StatusOK, contentTypeText, res)
+		return
+	}
+
+	renderCtx, cancel := context.WithTimeout(ctx.Request.Context(), SolveTimeout)
+	defer cancel()
+
+	opts := &model.RenderOptions{
+		Format:   format,
+		Solution: query.Solution,
+		CellSize: query.CellSize,
+		Style:    query.Style,
+		Labels:   query.Labels,
+	}
+	if opts.Style == "" {
+		opts.Style = model.TextStyleASCII
+	}
+	res, err := a.MazeService.RenderMaze(renderCtx, id, ctx.GetInt64(CTXUserID), opts)
 	if err != nil {
 		ctx.Error(err)
 		return
 	}
 
-	ctx.Data(http.StatusOK, "text/plain", res)
+	contentType := contentTypeText + "; charset=utf-8"
+	switch format {
+	case model.RenderFormatPNG:
+		contentType = contentTypePNG
+	case model.RenderFormatSVG:
+		contentType = contentTypeSVG
+	}
+	ctx.Data(http.StatusOK, contentType, res)
 }
 
 // GetAllMazes godoc
-// @Summary Get all mazes belonging to the current user
+// @Summary Get a page of mazes belonging to the current user
 // @ID GetAllMazes
 // @Tags Maze
 // @Accept json
 // @Produce json
 // @Security bearerAuth
+// @Param   limit         query  integer  false  "Page size, 100 by default"  minimum(1) maximum(1000)
+// @Param   after         query  string   false  "Cursor from the nextCursor field of the previous page"
+// @Param   minRows       query  integer  false  "Minimum number of rows"
+// @Param   maxRows       query  integer  false  "Maximum number of rows"
+// @Param   minCols       query  integer  false  "Minimum number of columns"
+// @Param   maxCols       query  integer  false  "Maximum number of columns"
+// @Param   createdAfter  query  string   false  "Created at or after, RFC 3339"
+// @Param   createdBefore query  string   false  "Created before, RFC 3339"
+// @Param   sort          query  string   false  "Sort order, prefix with - for descending"  Enums(id, -id, createdAt, -createdAt, gridSize, -gridSize)
+// @Param   fields        query  string   false  "Comma separated fields to return, all by default"  example(gridSize,entrance)
 // @Success 200 {object} GetAllMazesResponseDTO
 // @Failure 400 {object} Message
 // @Failure 500 {object} Message
-// @Failure 500 {object} Message
 // @Router /maze [get]
 func (a *App) GetAllMazes(ctx *gin.Context) {
-	res, err := a.MazeService.GetAll(ctx.GetInt64(CTXUserID))
+	var params ListMazesQueryDTO
+	err := ctx.ShouldBindQuery(&params)
 	if err != nil {
-		ctx.Error(err)
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+
+	fields, err := parseFields(params.Fields)
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
 		return
 	}
 
-	allMazes := &GetAllMazesResponseDTO{Mazes: make([]*MazeResponseDTO, len(res))}
-	for i, m := range res {
-		allMazes.Mazes[i] = &MazeResponseDTO{
-			ID: m.ID,
-			MazeDTO: MazeDTO{
-				GridSize: fmt.Sprintf("%dx%d", m.Rows, m.Cols),
-				Entrance: m.Entrance,
-				Walls:    m.Walls,
-			},
+	q := &model.MazeQuery{
+		UserID:        ctx.GetInt64(CTXUserID),
+		MinRows:       params.MinRows,
+		MaxRows:       params.MaxRows,
+		MinCols:       params.MinCols,
+		MaxCols:       params.MaxCols,
+		CreatedAfter:  params.CreatedAfter,
+		CreatedBefore: params.CreatedBefore,
+		SortBy:        strings.TrimPrefix(params.Sort, "-"),
+		Desc:          strings.HasPrefix(params.Sort, "-"),
+		Limit:         params.Limit,
+		WithoutWalls:  !fields["walls"],
+	}
+	if q.Limit == 0 {
+		q.Limit = DefaultPageSize
+	}
+	if params.After != "" {
+		q.After, err = decodeCursor(params.After)
+		if err != nil {
+			ctx.Error(err).SetType(BadRequestErrorType)
+			return
 		}
 	}
 
+	page, err := a.MazeService.List(q)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	allMazes := &GetAllMazesResponseDTO{
+		Mazes: make([]*MazeListItemDTO, len(page.Mazes)),
+		Total: page.Total,
+	}
+	for i, m := range page.Mazes {
+		allMazes.Mazes[i] = toMazeListItemDTO(m, fields)
+	}
+	if page.Next != nil {
+		allMazes.NextCursor = encodeCursor(page.Next)
+	}
+
 	ctx.JSON(http.StatusOK, allMazes)
 }
 
 // SolveMaze godoc
 // @Summary Solve a previously stored maze
+// @Description With all=true every shortest path is returned in paths, up to the limit, and total counts all of them.
+// @Description With bestEffort=true the longest path found so far is returned when the time limit is reached, optimal is false then.
 // @ID SolveMaze
 // @Tags Maze
 // @Accept json
 // @Produce json
 // @Security bearerAuth
 // @Param   id  		path     integer    true  "maze id"
-// @Param   steps   query     string     true  "Find shortest or longest path"       Enums(min, max)
+// @Param   steps   query     string     true  "Find the shortest, the longest or the cheapest path"       Enums(min, max, cheapest)
+// @Param   movement  query   string     false "Moves of the agent, the movement of the maze by default: orthogonal, diagonal without or with cutting corners, knight jumps"  Enums(4, 8, 8-cut, knight)
+// @Param   all     query     boolean    false "Return all shortest paths, steps=min only"
+// @Param   limit   query     integer    false "Maximum number of paths returned with all=true, 100 by default"  minimum(1) maximum(1000)
+// @Param   bestEffort  query  boolean   false "Return the longest path found before the time limit instead of failing, steps=max only"
+// @Param   format  query     string     false "With directions the path is also given as moves with repetitions, e.g. R1 D3, in directions and compass"  Enums(cells, directions)
 // @Success 201 {object} SolutionResponseDTO
 // @Failure 400 {object} Message
 // @Failure 403 {object} Message
@@ -201,22 +737,583 @@ func (a *App) SolveMaze(ctx *gin.Context) {
 		return
 	}
 
-	steps := ctx.Query("steps")
-	if steps != "min" && steps != "max" {
-		ctx.Error(errors.New("invalid steps value")).SetType(BadRequestErrorType)
+	opts, err := solveOptions(ctx)
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+
+	var query SolveMazeQueryDTO
+	err = ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+	if query.All && opts.Steps != service.StepsMin {
+		ctx.Error(errors.New("all paths can only be found with steps=min")).SetType(BadRequestErrorType)
+		return
+	}
+	directions, err := withDirections(ctx)
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+
+	started := time.Now()
+	progress := &service.Progress{}
+	solveCtx, cancel := context.WithTimeout(service.WithProgress(ctx.Request.Context(), progress), SolveTimeout)
+	defer cancel()
+
+	if query.All {
+		if query.Limit == 0 {
+			query.Limit = DefaultPathLimit
+		}
+		res, err := a.MazeService.ShortestPaths(solveCtx, id, ctx.GetInt64(CTXUserID), opts.Movement, query.Limit)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+
+		path := res.Paths[0]
+		writeSolution(ctx, &SolutionResponseDTO{
+			Path:      path,
+			Exit:      path[len(path)-1],
+			Cost:      res.Cost,
+			Paths:     res.Paths,
+			Total:     res.Total,
+			Optimal:   true,
+			ElapsedMs: elapsedMs(started),
+			Explored:  progress.Explored(),
+		}, directions)
+		return
+	}
+
+	if opts.Steps == service.StepsMax {
+		opts.Workers = a.solveWorkers()
+		solveCtx = service.WithSolveCores(solveCtx, &a.solveCores)
+	}
+	res, err := a.MazeService.Solve(solveCtx, id, ctx.GetInt64(CTXUserID), opts)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	writeSolution(ctx, &SolutionResponseDTO{
+		Path:      res.Path,
+		Exit:      res.Path[len(res.Path)-1],
+		Cost:      res.Cost,
+		Optimal:   res.Optimal,
+		ElapsedMs: elapsedMs(started),
+		Explored:  progress.Explored(),
+	}, directions)
+}
+
+// solveWorkers limits the goroutines of a longest path search to SolveWorkers or all cores. The search takes
+// only the cores not used by other searches, and only while it runs.
+func (a *App) solveWorkers() int {
+	if a.SolveWorkers <= 0 {
+		return runtime.NumCPU()
+	}
+	return a.SolveWorkers
+}
+
+// SolveUnsavedMaze godoc
+// @Summary Solve a maze without storing it
+// @Description With bestEffort=true the longest path found so far is returned when the time limit is reached, optimal is false then.
+// @ID SolveUnsavedMaze
+// @Tags Maze
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param maze body MazeDTO true "Maze description"
+// @Param   steps   query     string     true  "Find the shortest, the longest or the cheapest path"       Enums(min, max, cheapest)
+// @Param   movement  query   string     false "Moves of the agent, the movement of the maze by default: orthogonal, diagonal without or with cutting corners, knight jumps"  Enums(4, 8, 8-cut, knight)
+// @Param   bestEffort  query  boolean   false "Return the longest path found before the time limit instead of failing, steps=max only"
+// @Param   format  query     string     false "With directions the path is also given as moves with repetitions, e.g. R1 D3, in directions and compass"  Enums(cells, directions)
+// @Success 200 {object} SolutionResponseDTO
+// @Failure 400 {object} Message
+// @Failure 403 {object} Message
+// @Failure 408 {object} Message
+// @Failure 500 {object} Message
+// @Router /solve [post]
+func (a *App) SolveUnsavedMaze(ctx *gin.Context) {
+	opts, err := solveOptions(ctx)
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+	directions, err := withDirections(ctx)
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+
+	var maze MazeDTO
+	err = ctx.ShouldBindJSON(&maze)
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+
+	m, err := maze.toModel()
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+
+	started := time.Now()
+	progress := &service.Progress{}
+	solveCtx, cancel := context.WithTimeout(service.WithProgress(ctx.Request.Context(), progress), SolveTimeout)
+	defer cancel()
+
+	if opts.Steps == service.StepsMax {
+		opts.Workers = a.solveWorkers()
+		solveCtx = service.WithSolveCores(solveCtx, &a.solveCores)
+	}
+	path, optimal, err := service.SolveWith(solveCtx, m, opts)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	cost, err := service.PathCost(m, path)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	writeSolution(ctx, &SolutionResponseDTO{
+		Path:      path,
+		Exit:      path[len(path)-1],
+		Cost:      cost,
+		Optimal:   optimal,
+		ElapsedMs: elapsedMs(started),
+		Explored:  progress.Explored(),
+	}, directions)
+}
+
+// withDirections reads the format query parameter of solutions, it tells if directions are requested.
+func withDirections(ctx *gin.Context) (bool, error) {
+	switch ctx.Query("format") {
+	case "", PathFormatCells:
+		return false, nil
+	case PathFormatDirections:
+		return true, nil
+	default:
+		return false, errors.New("invalid format value")
+	}
+}
+
+// writeSolution adds the directions of the path if they are requested.
+func writeSolution(ctx *gin.Context, res *SolutionResponseDTO, directions bool) {
+	if directions {
+		var err error
+		res.Directions, err = service.PathToDirections(res.Path, false)
+		if err == nil {
+			res.Compass, err = service.PathToDirections(res.Path, true)
+		}
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func elapsedMs(started time.Time) float64 {
+	return float64(time.Since(started).Microseconds()) / 1000
+}
+
+// GetMazeStats godoc
+// @Summary Count the shortest and the simple paths of a previously stored maze
+// @ID GetMazeStats
+// @Tags Maze
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param   id  		path     integer    true  "maze id"
+// @Param   movement  query   string     false "Moves of the agent, the movement of the maze by default"  Enums(4, 8, 8-cut, knight)
+// @Success 200 {object} MazeStatsResponseDTO
+// @Failure 400 {object} Message
+// @Failure 403 {object} Message
+// @Failure 404 {object} Message
+// @Failure 408 {object} Message
+// @Failure 500 {object} Message
+// @Router /maze/{id}/stats [get]
+func (a *App) GetMazeStats(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 0, 64)
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+
+	movement := ctx.Query("movement")
+	if !service.IsValidMovement(movement) {
+		ctx.Error(errors.New("invalid movement value")).SetType(BadRequestErrorType)
 		return
 	}
 
-	solveCtx, cancel := context.WithTimeout(ctx.Request.Context(), SolveTimeout)
+	statsCtx, cancel := context.WithTimeout(ctx.Request.Context(), SolveTimeout)
 	defer cancel()
 
-	res, err := a.MazeService.Solve(solveCtx, id, ctx.GetInt64(CTXUserID), steps)
+	res, err := a.MazeService.Stats(statsCtx, id, ctx.GetInt64(CTXUserID), movement)
 	if err != nil {
 		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, &SolutionResponseDTO{
-		Path: res,
+	ctx.JSON(http.StatusOK, &MazeStatsResponseDTO{
+		ShortestPathLength: res.ShortestPathLength,
+		ShortestPaths:      res.ShortestPaths,
+		SimplePaths:        res.SimplePaths,
 	})
 }
+
+// GetMazeAnalysis godoc
+// @Summary Describe the structure and the difficulty of a previously stored maze
+// @ID GetMazeAnalysis
+// @Tags Maze
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param   id  		path     integer    true  "maze id"
+// @Param   movement  query   string     false "Moves of the agent, the movement of the maze by default"  Enums(4, 8, 8-cut, knight)
+// @Success 200 {object} MazeAnalysisResponseDTO
+// @Failure 400 {object} Message
+// @Failure 403 {object} Message
+// @Failure 404 {object} Message
+// @Failure 408 {object} Message
+// @Failure 500 {object} Message
+// @Router /maze/{id}/analysis [get]
+func (a *App) GetMazeAnalysis(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 0, 64)
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+
+	movement := ctx.Query("movement")
+	if !service.IsValidMovement(movement) {
+		ctx.Error(errors.New("invalid movement value")).SetType(BadRequestErrorType)
+		return
+	}
+
+	analysisCtx, cancel := context.WithTimeout(ctx.Request.Context(), SolveTimeout)
+	defer cancel()
+
+	res, err := a.MazeService.Analyze(analysisCtx, id, ctx.GetInt64(CTXUserID), movement)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	chokepoints := res.Chokepoints
+	if chokepoints == nil {
+		chokepoints = []string{}
+	}
+	ctx.JSON(http.StatusOK, &MazeAnalysisResponseDTO{
+		ReachableCells:     res.ReachableCells,
+		UnreachableRegions: res.UnreachableRegions,
+		UnreachableCells:   res.UnreachableCells,
+		DeadEnds:           res.DeadEnds,
+		BranchingFactor:    res.BranchingFactor,
+		Chokepoints:        chokepoints,
+		ShortestPathLength: res.ShortestPathLength,
+		LongestPathLength:  res.LongestPathLength,
+		LongestPathOptimal: res.LongestPathOptimal,
+		Difficulty:         res.Difficulty,
+	})
+}
+
+// VerifyPath godoc
+// @Summary Check a path submitted by a player
+// @Description A valid path starts at the entrance, every step is a move allowed in the maze, it doesn't visit any cell twice
+// @Description and ends at the first exit it reaches. Every problem found is listed in errors with its position in the path.
+// @Description Instead of the cells, the path can be given as directions from the entrance in the format of GET /maze/{id}/solution?format=directions.
+// @ID VerifyPath
+// @Tags Maze
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param   id  		path     integer    true  "maze id"
+// @Param   movement  query   string     false "Moves of the agent, the movement of the maze by default"  Enums(4, 8, 8-cut, knight)
+// @Param path body VerifyPathDTO true "Cells of the path or directions from the entrance"
+// @Success 200 {object} PathVerificationResponseDTO
+// @Failure 400 {object} Message
+// @Failure 403 {object} Message
+// @Failure 404 {object} Message
+// @Failure 408 {object} Message
+// @Failure 500 {object} Message
+// @Router /maze/{id}/verify [post]
+func (a *App) VerifyPath(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 0, 64)
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+
+	movement := ctx.Query("movement")
+	if !service.IsValidMovement(movement) {
+		ctx.Error(errors.New("invalid movement value")).SetType(BadRequestErrorType)
+		return
+	}
+
+	var body VerifyPathDTO
+	err = ctx.ShouldBindJSON(&body)
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+	if (body.Path == nil) == (body.Directions == "") {
+		ctx.Error(errors.New("either path or directions is required")).SetType(BadRequestErrorType)
+		return
+	}
+	if body.Directions != "" {
+		maze, err := a.MazeService.GetByID(id, ctx.GetInt64(CTXUserID))
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		body.Path, err = service.DirectionsToPath(maze.Entrance, body.Directions, maze.Rows*maze.Cols)
+		if err != nil {
+			ctx.Error(err).SetType(BadRequestErrorType)
+			return
+		}
+	}
+
+	verifyCtx, cancel := context.WithTimeout(ctx.Request.Context(), SolveTimeout)
+	defer cancel()
+
+	res, err := a.MazeService.Verify(verifyCtx, id, ctx.GetInt64(CTXUserID), movement, body.Path)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	errs := make([]PathErrorDTO, len(res.Errors))
+	for i, e := range res.Errors {
+		errs[i] = PathErrorDTO{Index: e.Index, Cell: e.Cell, Message: e.Message}
+	}
+	ctx.JSON(http.StatusOK, &PathVerificationResponseDTO{
+		Valid:              res.Valid,
+		Length:             res.Length,
+		Cost:               res.Cost,
+		ShortestPathLength: res.ShortestPathLength,
+		ExtraSteps:         res.ExtraSteps,
+		Errors:             errs,
+	})
+}
+
+// solveOptions reads the steps, movement and bestEffort query parameters.
+func solveOptions(ctx *gin.Context) (*model.SolveOptions, error) {
+	opts := &model.SolveOptions{
+		Steps:    ctx.Query("steps"),
+		Movement: ctx.Query("movement"),
+	}
+	if !service.IsValidSteps(opts.Steps) {
+		return nil, errors.New("invalid steps value")
+	}
+	if !service.IsValidMovement(opts.Movement) {
+		return nil, errors.New("invalid movement value")
+	}
+	if bestEffort, ok := ctx.GetQuery("bestEffort"); ok {
+		var err error
+		opts.BestEffort, err = strconv.ParseBool(bestEffort)
+		if err != nil {
+			return nil, errors.New("invalid bestEffort value")
+		}
+	}
+	if opts.BestEffort && opts.Steps != service.StepsMax {
+		return nil, errors.New("bestEffort can only be used with steps=max")
+	}
+	return opts, nil
+}
+
+func gridSize(rows, cols int) string {
+	return fmt.Sprintf("%dx%d", rows, cols)
+}
+
+// toModel validates the maze. The maze has no ID and no user yet.
+func (m *MazeDTO) toModel() (*model.Maze, error) {
+	rows, cols, _, _, err := service.ValidateMaze(m.GridSize, m.Entrance, m.Walls, m.ExitMode, m.Exits, m.Movement)
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.ValidateCosts(rows, cols, m.Walls, m.Costs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Maze{
+		Rows:     rows,
+		Cols:     cols,
+		Entrance: m.Entrance,
+		Walls:    m.Walls,
+		ExitMode: m.exitMode(),
+		Exits:    m.Exits,
+		Costs:    m.Costs,
+		Movement: movementOf(m.Movement),
+	}, nil
+}
+
+func (m *MazeDTO) exitMode() string {
+	switch {
+	case m.ExitMode != "":
+		return m.ExitMode
+	case len(m.Exits) > 0:
+		return model.ExitModeExplicit
+	default:
+		return model.ExitModeSingle
+	}
+}
+
+// Mazes stored before movements were introduced have none, they are orthogonal.
+func movementOf(movement string) string {
+	if movement == "" {
+		return model.MovementOrthogonal
+	}
+	return movement
+}
+
+// Mazes stored before exit modes were introduced have none, they are single-exit.
+func exitModeOf(m *model.Maze) string {
+	if m.ExitMode == "" {
+		return model.ExitModeSingle
+	}
+	return m.ExitMode
+}
+
+func toMazeResponseDTO(m *model.Maze) *MazeResponseDTO {
+	return &MazeResponseDTO{
+		ID: m.ID,
+		MazeDTO: MazeDTO{
+			GridSize: gridSize(m.Rows, m.Cols),
+			Entrance: m.Entrance,
+			Walls:    m.Walls,
+			ExitMode: exitModeOf(m),
+			Exits:    m.Exits,
+			Costs:    m.Costs,
+			Movement: movementOf(m.Movement),
+		},
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+func toMazeListItemDTO(m *model.Maze, fields map[string]bool) *MazeListItemDTO {
+	res := &MazeListItemDTO{ID: m.ID}
+	if fields["gridSize"] {
+		res.GridSize = gridSize(m.Rows, m.Cols)
+	}
+	if fields["entrance"] {
+		res.Entrance = m.Entrance
+	}
+	if fields["walls"] {
+		walls := m.Walls
+		res.Walls = &walls
+	}
+	if fields["exitMode"] {
+		res.ExitMode = exitModeOf(m)
+	}
+	if fields["exits"] {
+		res.Exits = m.Exits
+	}
+	if fields["costs"] {
+		res.Costs = m.Costs
+	}
+	if fields["movement"] {
+		res.Movement = movementOf(m.Movement)
+	}
+	if fields["createdAt"] {
+		createdAt := m.CreatedAt
+		res.CreatedAt = &createdAt
+	}
+	return res
+}
+
+// parseFields parses a comma separated list of maze fields, empty list means all fields.
+func parseFields(s string) (map[string]bool, error) {
+	res := map[string]bool{}
+	if s == "" {
+		for _, f := range mazeListFields {
+			res[f] = true
+		}
+		return res, nil
+	}
+
+	for _, f := range strings.Split(s, ",") {
+		if !contains(mazeListFields, f) {
+			return nil, errors.New("invalid field: " + f)
+		}
+		res[f] = true
+	}
+	return res, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Cursors are opaque to clients, they are base64 encoded JSON.
+
+func encodeCursor(c *model.MazeCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (*model.MazeCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	var c model.MazeCursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	return &c, nil
+}
+
+// patchWalls removes and then adds walls, keeping the original order and skipping duplicates.
+// Cells are compared in canonical spelling, e.g. A01 matches A1.
+func patchWalls(walls, add, remove []string) []string {
+	removed := make(map[string]bool, len(remove))
+	for _, w := range remove {
+		removed[canonicalCell(w)] = true
+	}
+
+	res := make([]string, 0, len(walls)+len(add))
+	present := make(map[string]bool, len(walls)+len(add))
+	for _, w := range walls {
+		c := canonicalCell(w)
+		if removed[c] || present[c] {
+			continue
+		}
+		present[c] = true
+		res = append(res, w)
+	}
+	for _, w := range add {
+		c := canonicalCell(w)
+		if present[c] {
+			continue
+		}
+		present[c] = true
+		res = append(res, w)
+	}
+	return res
+}
+
+// canonicalCell spells a cell like model.FormatA1, e.g. A01 as A1. Invalid cells are returned as they are,
+// validation rejects them.
+func canonicalCell(cell string) string {
+	row, col, err := model.ParseA1(cell)
+	if err != nil {
+		return cell
+	}
+	return model.FormatA1(row, col)
+}
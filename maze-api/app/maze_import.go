@@ -0,0 +1,237 @@
+package app
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/egurnov/maze-api/maze-api/model"
+)
+
+const (
+	MaxImportMazes = 1000
+	// Larger import bodies are rejected before they are read completely
+	MaxImportBytes = 16 << 20
+	// Mazes are exported in batches of this size, each batch is flushed to the client
+	ExportBatchSize = 100
+)
+
+type ImportResponseDTO struct {
+	Imported int             `json:"imported"`
+	Failed   int             `json:"failed"`
+	Items    []ImportItemDTO `json:"items"`
+}
+
+type ImportItemDTO struct {
+	Index int    `json:"index"` // Position in the input, from 0
+	ID    int64  `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// ImportMazes godoc
+// @Summary Create many mazes at once
+// @Description The body is a JSON array of mazes or NDJSON, one maze per line, up to 1000 mazes and 16 MiB. Every maze is validated on its own,
+// @Description invalid ones are reported in items and the valid ones are stored, unless atomic=true.
+// @Description With atomic=true nothing is stored if any maze is invalid and the response is 400 with the errors in items.
+// @ID ImportMazes
+// @Tags Maze
+// @Accept json
+// @Produce json
+// @Security bearerAuth
+// @Param mazes body []MazeDTO true "Maze descriptions"
+// @Param   atomic  query   boolean  false "Store all mazes or none of them"
+// @Success 200 {object} ImportResponseDTO
+// @Failure 400 {object} ImportResponseDTO
+// @Failure 403 {object} Message
+// @Failure 413 {object} Message
+// @Failure 500 {object} Message
+// @Router /maze/import [post]
+func (a *App) ImportMazes(ctx *gin.Context) {
+	allOrNothing := false
+	if value, ok := ctx.GetQuery("atomic"); ok {
+		var err error
+		allOrNothing, err = strconv.ParseBool(value)
+		if err != nil {
+			ctx.Error(errors.New("invalid atomic value")).SetType(BadRequestErrorType)
+			return
+		}
+	}
+
+	items, err := readJSONItems(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, MaxImportBytes), MaxImportMazes)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		ctx.JSON(http.StatusRequestEntityTooLarge, &Message{Message: fmt.Sprintf("the body can have at most %d bytes", MaxImportBytes)})
+		return
+	}
+	if err != nil {
+		ctx.Error(err).SetType(BadRequestErrorType)
+		return
+	}
+	if len(items) == 0 {
+		ctx.Error(errors.New("no mazes to import")).SetType(BadRequestErrorType)
+		return
+	}
+
+	res := &ImportResponseDTO{Items: make([]ImportItemDTO, len(items))}
+	var mazes []*model.Maze
+	var indexes []int
+	for i, item := range items {
+		res.Items[i].Index = i
+		maze, err := importedMaze(item)
+		if err != nil {
+			res.Items[i].Error = err.Error()
+			res.Failed++
+			continue
+		}
+		maze.UserID = ctx.GetInt64(CTXUserID)
+		mazes = append(mazes, maze)
+		indexes = append(indexes, i)
+	}
+	if res.Failed > 0 && allOrNothing {
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	if len(mazes) > 0 {
+		ids, err := a.MazeService.CreateMany(mazes)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		for i, id := range ids {
+			res.Items[indexes[i]].ID = id
+		}
+		res.Imported = len(ids)
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// ExportMazes godoc
+// @Summary Stream all mazes of the current user as NDJSON, one maze per line
+// @Description The output can be imported with POST /maze/import. Mazes are sent in batches, ordered by ID.
+// @ID ExportMazes
+// @Tags Maze
+// @Produce application/x-ndjson
+// @Security bearerAuth
+// @Success 200 {object} MazeResponseDTO
+// @Failure 403 {object} Message
+// @Failure 500 {object} Message
+// @Router /maze/export [get]
+func (a *App) ExportMazes(ctx *gin.Context) {
+	q := &model.MazeQuery{UserID: ctx.GetInt64(CTXUserID), Limit: ExportBatchSize}
+	page, err := a.MazeService.List(q)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("Content-Type", "application/x-ndjson")
+	ctx.Status(http.StatusOK)
+	enc := json.NewEncoder(ctx.Writer)
+	for {
+		for _, maze := range page.Mazes {
+			// The client is gone if writing fails
+			if err := enc.Encode(toMazeResponseDTO(maze)); err != nil {
+				return
+			}
+		}
+		ctx.Writer.Flush()
+		if page.Next == nil {
+			return
+		}
+
+		q.After = page.Next
+		page, err = a.MazeService.List(q)
+		if err != nil {
+			// The status is already sent, the client gets a truncated stream
+			a.Log.WithField("url", ctx.Request.URL.String()).Error(err)
+			return
+		}
+	}
+}
+
+// readJSONItems reads up to limit items of a JSON array or of a stream of JSON values, e.g. NDJSON.
+// Items are decoded separately, so that an invalid item doesn't fail the others.
+// Reading stops at the first item over the limit.
+func readJSONItems(r io.Reader, limit int) ([]json.RawMessage, error) {
+	tooMany := fmt.Errorf("at most %d mazes can be imported at once", limit)
+	br := bufio.NewReader(r)
+	isArray, err := startsWithArray(br)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(br)
+
+	// A single array holds all items
+	if isArray {
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+	}
+
+	var items []json.RawMessage
+	for !isArray || dec.More() {
+		var item json.RawMessage
+		err := dec.Decode(&item)
+		if err == io.EOF && !isArray {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+		if len(items) > limit {
+			return nil, tooMany
+		}
+	}
+
+	if isArray {
+		// The closing bracket ends the input
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		if _, err := dec.Token(); err != io.EOF {
+			return nil, errors.New("unexpected data after the array")
+		}
+	}
+
+	return items, nil
+}
+
+// startsWithArray tells if the first character after white space is [, without consuming it.
+func startsWithArray(r *bufio.Reader) (bool, error) {
+	for {
+		c, err := r.ReadByte()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return c == '[', r.UnreadByte()
+	}
+}
+
+func importedMaze(item json.RawMessage) (*model.Maze, error) {
+	var maze MazeDTO
+	if err := json.Unmarshal(item, &maze); err != nil {
+		return nil, err
+	}
+	if err := binding.Validator.ValidateStruct(&maze); err != nil {
+		return nil, err
+	}
+	return maze.toModel()
+}
@@ -40,6 +40,12 @@ func (a *App) renderErrors() gin.HandlerFunc {
 				ctx.JSON(http.StatusBadRequest, &Message{Message: err.Error()})
 			case model.ErrorTimelimitReached:
 				ctx.JSON(http.StatusRequestTimeout, &Message{Message: err.Error()})
+			case model.ErrorImageTooLarge:
+				ctx.JSON(http.StatusBadRequest, &Message{Message: err.Error()})
+			case model.ErrConflict:
+				ctx.JSON(http.StatusConflict, &Message{Message: err.Error()})
+			case model.ErrorTooManyJobs:
+				ctx.JSON(http.StatusTooManyRequests, &Message{Message: err.Error()})
 			default:
 				switch err.Type {
 				case BadRequestErrorType:
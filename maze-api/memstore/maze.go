@@ -0,0 +1,180 @@
+package memstore
+
+import (
+	"sort"
+	"time"
+
+	"github.com/egurnov/maze-api/maze-api/model"
+)
+
+var _ model.MazeStore = &MazeStore{&Store{}}
+
+type MazeStore struct{ *Store }
+
+func (s *MazeStore) GetByID(id, userId int64) (*model.Maze, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	maze, ok := s.mazes[id]
+	if !ok || maze.UserID != userId {
+		return nil, model.ErrNotFound
+	}
+	return copyMaze(maze), nil
+}
+
+func (s *MazeStore) GetAll(userId int64) ([]*model.Maze, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	res := []*model.Maze{}
+	for _, maze := range s.mazes {
+		if maze.UserID == userId {
+			res = append(res, copyMaze(maze))
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+
+	return res, nil
+}
+
+func (s *MazeStore) List(q *model.MazeQuery) ([]*model.Maze, int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var res []*model.Maze
+	for _, maze := range s.mazes {
+		if maze.UserID == q.UserID && matches(maze, q) {
+			res = append(res, copyMaze(maze))
+		}
+	}
+	total := int64(len(res))
+
+	// Sort by the requested key, ID breaks ties and makes the order stable
+	key := func(m *model.Maze) int64 {
+		return sortKey(q.SortBy, m.ID, m.CreatedAt, m.Rows*m.Cols)
+	}
+	less := func(ka, ida, kb, idb int64) bool {
+		if q.Desc {
+			return ka > kb || (ka == kb && ida > idb)
+		}
+		return ka < kb || (ka == kb && ida < idb)
+	}
+	sort.Slice(res, func(i, j int) bool { return less(key(res[i]), res[i].ID, key(res[j]), res[j].ID) })
+
+	if c := q.After; c != nil {
+		afterKey := sortKey(q.SortBy, c.ID, c.CreatedAt, c.GridSize)
+		i := sort.Search(len(res), func(i int) bool { return less(afterKey, c.ID, key(res[i]), res[i].ID) })
+		res = res[i:]
+	}
+	if q.Limit > 0 && len(res) > q.Limit {
+		res = res[:q.Limit]
+	}
+	if q.WithoutWalls {
+		for _, m := range res {
+			m.Walls = nil
+		}
+	}
+
+	return res, total, nil
+}
+
+func sortKey(sortBy string, id int64, createdAt time.Time, gridSize int) int64 {
+	switch sortBy {
+	case model.MazeSortCreatedAt:
+		return createdAt.UnixNano()
+	case model.MazeSortGridSize:
+		return int64(gridSize)
+	default:
+		return id
+	}
+}
+
+func matches(maze *model.Maze, q *model.MazeQuery) bool {
+	return (q.MinRows == 0 || maze.Rows >= q.MinRows) &&
+		(q.MaxRows == 0 || maze.Rows <= q.MaxRows) &&
+		(q.MinCols == 0 || maze.Cols >= q.MinCols) &&
+		(q.MaxCols == 0 || maze.Cols <= q.MaxCols) &&
+		(q.CreatedAfter.IsZero() || !maze.CreatedAt.Before(q.CreatedAfter)) &&
+		(q.CreatedBefore.IsZero() || maze.CreatedAt.Before(q.CreatedBefore))
+}
+
+func (s *MazeStore) Create(maze *model.Maze) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.create(maze), nil
+}
+
+func (s *MazeStore) CreateMany(mazes []*model.Maze) ([]int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]int64, len(mazes))
+	for i, maze := range mazes {
+		ids[i] = s.create(maze)
+	}
+
+	return ids, nil
+}
+
+// create stores a copy of the maze and returns its ID. The caller must hold the lock.
+func (s *MazeStore) create(maze *model.Maze) int64 {
+	s.lastMazeID++
+	dbMaze := copyMaze(maze)
+	dbMaze.ID = s.lastMazeID
+	if dbMaze.CreatedAt.IsZero() {
+		dbMaze.CreatedAt = time.Now().UTC()
+	}
+	dbMaze.Version = 1
+	s.mazes[dbMaze.ID] = dbMaze
+
+	return dbMaze.ID
+}
+
+func (s *MazeStore) Update(maze *model.Maze) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.mazes[maze.ID]
+	if !ok || old.UserID != maze.UserID {
+		return model.ErrNotFound
+	}
+	if maze.Version != 0 && maze.Version != old.Version {
+		return model.ErrConflict
+	}
+	s.mazes[maze.ID] = copyMaze(maze)
+	s.mazes[maze.ID].CreatedAt = old.CreatedAt
+	s.mazes[maze.ID].Version = old.Version + 1
+	delete(s.solutions, maze.ID)
+
+	return nil
+}
+
+func (s *MazeStore) Delete(id, userId int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	maze, ok := s.mazes[id]
+	if !ok || maze.UserID != userId {
+		return model.ErrNotFound
+	}
+	delete(s.mazes, id)
+	delete(s.solutions, id)
+
+	return nil
+}
+
+func copyMaze(maze *model.Maze) *model.Maze {
+	res := *maze
+	res.Walls = append([]string{}, maze.Walls...)
+	if maze.Exits != nil {
+		res.Exits = append([]string{}, maze.Exits...)
+	}
+	if maze.Costs != nil {
+		res.Costs = make(map[string]int, len(maze.Costs))
+		for cell, cost := range maze.Costs {
+			res.Costs[cell] = cost
+		}
+	}
+	return &res
+}
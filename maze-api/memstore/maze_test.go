@@ -0,0 +1,216 @@
+package memstore_test
+
+import (
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/egurnov/maze-api/maze-api/memstore"
+	"github.com/egurnov/maze-api/maze-api/model"
+)
+
+var _ = Describe("MazeStore", func() {
+	var s *memstore.MazeStore
+
+	BeforeEach(func() {
+		s = &memstore.MazeStore{Store: memstore.New()}
+	})
+
+	AfterEach(func() {
+		Expect(s.Wipe()).To(Succeed())
+		Expect(s.Close()).To(Succeed())
+	})
+
+	Specify("full flow", func() {
+		maze := &model.Maze{
+			Rows:      4,
+			Cols:      4,
+			Entrance:  "A1",
+			Walls:     []string{"A4", "B4", "C4"},
+			ExitMode:  model.ExitModeExplicit,
+			Exits:     []string{"D4"},
+			Costs:     map[string]int{"B2": 5},
+			CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			UserID:    1,
+		}
+
+		id, err := s.Create(maze)
+		Expect(err).ToNot(HaveOccurred())
+		maze.ID, maze.Version = id, 1
+
+		found, err := s.GetByID(id, 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(Equal(maze))
+
+		By("returned copies don't affect stored data")
+		found.Walls[0] = "D4"
+		found.Exits[0] = "D1"
+		found.Costs["B2"] = 1
+		found, err = s.GetByID(id, 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(Equal(maze))
+
+		By("other user can't see the maze")
+		_, err = s.GetByID(id, 2)
+		Expect(err).To(MatchError(model.ErrNotFound))
+
+		all, err := s.GetAll(2)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(all).To(BeEmpty())
+
+		all, err = s.GetAll(1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(all).To(Equal([]*model.Maze{maze}))
+
+		By("update")
+		maze.Entrance = "B1"
+		maze.Walls = []string{"A4", "C4", "D4"}
+		Expect(s.Update(maze)).To(Succeed())
+		maze.Version++
+
+		found, err = s.GetByID(id, 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(Equal(maze))
+
+		other := *maze
+		other.UserID = 2
+		Expect(s.Update(&other)).To(MatchError(model.ErrNotFound))
+
+		By("update of a stale version")
+		stale := *maze
+		stale.Version--
+		stale.Entrance = "A1"
+		Expect(s.Update(&stale)).To(MatchError(model.ErrConflict))
+
+		found, err = s.GetByID(id, 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(Equal(maze))
+
+		By("update without a version")
+		unversioned := *maze
+		unversioned.Version = 0
+		Expect(s.Update(&unversioned)).To(Succeed())
+		maze.Version++
+
+		By("delete")
+		Expect(s.Delete(id, 2)).To(MatchError(model.ErrNotFound))
+		Expect(s.Delete(id, 1)).To(Succeed())
+		Expect(s.Delete(id, 1)).To(MatchError(model.ErrNotFound))
+
+		_, err = s.GetByID(id, 1)
+		Expect(err).To(MatchError(model.ErrNotFound))
+	})
+
+	Specify("create many", func() {
+		mazes := []*model.Maze{
+			{Rows: 2, Cols: 2, Entrance: "A1", Walls: []string{"B1"}, CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), UserID: 1},
+			{Rows: 3, Cols: 3, Entrance: "B1", Walls: []string{"B2"}, Exits: []string{"C3"}, CreatedAt: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), UserID: 1},
+		}
+		ids, err := s.CreateMany(mazes)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ids).To(HaveLen(2))
+		mazes[0].ID, mazes[1].ID = ids[0], ids[1]
+		mazes[0].Version, mazes[1].Version = 1, 1
+
+		all, err := s.GetAll(1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(all).To(Equal(mazes))
+	})
+
+	Specify("list", func() {
+		start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		var ids []int64
+		for i, size := range [][2]int{{2, 2}, {5, 5}, {3, 4}, {10, 10}, {4, 3}} {
+			id, err := s.Create(&model.Maze{
+				Rows:      size[0],
+				Cols:      size[1],
+				Entrance:  "A1",
+				Walls:     []string{"B1"},
+				CreatedAt: start.Add(time.Duration(5-i) * time.Hour),
+				UserID:    1,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			ids = append(ids, id)
+		}
+		_, err := s.Create(&model.Maze{Rows: 2, Cols: 2, Entrance: "A1", UserID: 2})
+		Expect(err).ToNot(HaveOccurred())
+
+		listIDs := func(q *model.MazeQuery) ([]int64, int64) {
+			q.UserID = 1
+			mazes, total, err := s.List(q)
+			Expect(err).ToNot(HaveOccurred())
+			res := []int64{}
+			for _, m := range mazes {
+				res = append(res, m.ID)
+			}
+			return res, total
+		}
+
+		By("no filters")
+		res, total := listIDs(&model.MazeQuery{})
+		Expect(res).To(Equal(ids))
+		Expect(total).To(Equal(int64(5)))
+
+		By("limit and cursor")
+		res, total = listIDs(&model.MazeQuery{Limit: 2})
+		Expect(res).To(Equal(ids[:2]))
+		Expect(total).To(Equal(int64(5)))
+		res, _ = listIDs(&model.MazeQuery{Limit: 2, After: &model.MazeCursor{ID: ids[1]}})
+		Expect(res).To(Equal(ids[2:4]))
+
+		By("filters")
+		res, total = listIDs(&model.MazeQuery{MinRows: 3, MaxCols: 5})
+		Expect(res).To(Equal([]int64{ids[1], ids[2], ids[4]}))
+		Expect(total).To(Equal(int64(3)))
+		res, _ = listIDs(&model.MazeQuery{MinCols: 4, MaxRows: 5})
+		Expect(res).To(Equal([]int64{ids[1], ids[2]}))
+		res, _ = listIDs(&model.MazeQuery{CreatedAfter: start.Add(2 * time.Hour), CreatedBefore: start.Add(4 * time.Hour)})
+		Expect(res).To(Equal([]int64{ids[2], ids[3]}))
+
+		By("sorting")
+		res, _ = listIDs(&model.MazeQuery{SortBy: model.MazeSortID, Desc: true})
+		Expect(res).To(Equal([]int64{ids[4], ids[3], ids[2], ids[1], ids[0]}))
+		res, _ = listIDs(&model.MazeQuery{SortBy: model.MazeSortCreatedAt})
+		Expect(res).To(Equal([]int64{ids[4], ids[3], ids[2], ids[1], ids[0]}))
+		res, _ = listIDs(&model.MazeQuery{SortBy: model.MazeSortGridSize, Desc: true})
+		Expect(res).To(Equal([]int64{ids[3], ids[1], ids[4], ids[2], ids[0]}))
+		res, _ = listIDs(&model.MazeQuery{SortBy: model.MazeSortGridSize, Desc: true, Limit: 2,
+			After: &model.MazeCursor{ID: ids[4], GridSize: 12}})
+		Expect(res).To(Equal([]int64{ids[2], ids[0]}))
+		res, _ = listIDs(&model.MazeQuery{SortBy: model.MazeSortCreatedAt, Limit: 2,
+			After: &model.MazeCursor{ID: ids[3], CreatedAt: start.Add(2 * time.Hour)}})
+		Expect(res).To(Equal([]int64{ids[2], ids[1]}))
+
+		By("without walls")
+		mazes, _, err := s.List(&model.MazeQuery{UserID: 1, Limit: 1, WithoutWalls: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mazes).To(HaveLen(1))
+		Expect(mazes[0].Walls).To(BeNil())
+		Expect(mazes[0].Rows).To(Equal(2))
+		Expect(mazes[0].CreatedAt.Equal(start.Add(5 * time.Hour))).To(BeTrue())
+	})
+
+	Specify("concurrent creation", func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer GinkgoRecover()
+
+				_, err := s.Create(&model.Maze{Rows: 2, Cols: 2, Entrance: "A1", UserID: 1})
+				Expect(err).ToNot(HaveOccurred())
+			}()
+		}
+		wg.Wait()
+
+		all, err := s.GetAll(1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(all).To(HaveLen(100))
+		for i, m := range all {
+			Expect(m.ID).To(Equal(int64(i + 1)))
+		}
+	})
+})
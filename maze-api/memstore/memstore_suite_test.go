@@ -0,0 +1,13 @@
+package memstore_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestMemStore(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "MemStore Suite")
+}
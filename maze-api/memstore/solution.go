@@ -0,0 +1,40 @@
+package memstore
+
+import (
+	"github.com/egurnov/maze-api/maze-api/model"
+)
+
+var _ model.SolutionStore = &SolutionStore{&Store{}}
+
+type SolutionStore struct{ *Store }
+
+// Solutions are removed by maze updates under the same lock, so the ones left are for the current version.
+func (s *SolutionStore) Get(mazeID, mazeVersion int64, steps string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	maze, ok := s.mazes[mazeID]
+	if !ok || maze.Version != mazeVersion {
+		return nil, model.ErrNotFound
+	}
+	path, ok := s.solutions[mazeID][steps]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return append([]string{}, path...), nil
+}
+
+func (s *SolutionStore) Save(mazeID, mazeVersion int64, steps string, path []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if maze, ok := s.mazes[mazeID]; !ok || maze.Version != mazeVersion {
+		return model.ErrNotFound
+	}
+	if s.solutions[mazeID] == nil {
+		s.solutions[mazeID] = map[string][]string{}
+	}
+	s.solutions[mazeID][steps] = append([]string{}, path...)
+
+	return nil
+}
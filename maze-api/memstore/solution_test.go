@@ -0,0 +1,67 @@
+package memstore_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/egurnov/maze-api/maze-api/memstore"
+	"github.com/egurnov/maze-api/maze-api/model"
+)
+
+var _ = Describe("SolutionStore", func() {
+	var (
+		s      *memstore.SolutionStore
+		mazes  *memstore.MazeStore
+		maze   *model.Maze
+		mazeID int64
+	)
+
+	BeforeEach(func() {
+		store := memstore.New()
+		s = &memstore.SolutionStore{Store: store}
+		mazes = &memstore.MazeStore{Store: store}
+
+		maze = &model.Maze{Rows: 2, Cols: 2, Entrance: "A1", Walls: []string{"B1", "B2"}, UserID: 1}
+		var err error
+		mazeID, err = mazes.Create(maze)
+		Expect(err).ToNot(HaveOccurred())
+		maze.ID = mazeID
+	})
+
+	Specify("full flow", func() {
+		_, err := s.Get(mazeID, 1, "min")
+		Expect(err).To(MatchError(model.ErrNotFound))
+
+		path := []string{"A1", "A2"}
+		Expect(s.Save(mazeID, 1, "min", path)).To(Succeed())
+		Expect(s.Save(mazeID, 1, "max", path)).To(Succeed())
+		Expect(s.Get(mazeID, 1, "min")).To(Equal([]string{"A1", "A2"}))
+
+		By("stored data is a copy")
+		path[0] = "B1"
+		Expect(s.Get(mazeID, 1, "min")).To(Equal([]string{"A1", "A2"}))
+
+		By("unknown maze")
+		Expect(s.Save(mazeID+1, 1, "min", path)).To(MatchError(model.ErrNotFound))
+
+		By("update removes solutions")
+		maze.Walls = []string{"B1"}
+		Expect(mazes.Update(maze)).To(Succeed())
+		_, err = s.Get(mazeID, 2, "min")
+		Expect(err).To(MatchError(model.ErrNotFound))
+		_, err = s.Get(mazeID, 2, "max")
+		Expect(err).To(MatchError(model.ErrNotFound))
+
+		By("solutions of the old version aren't saved")
+		Expect(s.Save(mazeID, 1, "min", []string{"A1", "A2"})).To(MatchError(model.ErrNotFound))
+		Expect(s.Save(mazeID, 2, "min", []string{"A1", "A2"})).To(Succeed())
+		Expect(s.Get(mazeID, 2, "min")).To(Equal([]string{"A1", "A2"}))
+		_, err = s.Get(mazeID, 1, "min")
+		Expect(err).To(MatchError(model.ErrNotFound))
+
+		By("delete removes solutions")
+		Expect(mazes.Delete(mazeID, 1)).To(Succeed())
+		_, err = s.Get(mazeID, 2, "min")
+		Expect(err).To(MatchError(model.ErrNotFound))
+	})
+})
@@ -0,0 +1,45 @@
+package memstore
+
+import (
+	"sync"
+
+	"github.com/egurnov/maze-api/maze-api/model"
+)
+
+// Store keeps users, mazes and their solutions in process memory. It is safe for concurrent use,
+// but all data is lost when the process exits.
+type Store struct {
+	mu sync.RWMutex
+
+	users      map[int64]*model.User
+	mazes      map[int64]*model.Maze
+	solutions  map[int64]map[string][]string // maze ID -> steps -> path
+	lastUserID int64
+	lastMazeID int64
+}
+
+func New() *Store {
+	s := &Store{}
+	s.reset()
+	return s
+}
+
+func (s *Store) Close() error {
+	return nil
+}
+
+// Wipe deletes all data. Like auto-increment columns in SQL databases, IDs are not reused,
+// so that solutions cached by ID stay unambiguous.
+func (s *Store) Wipe() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reset()
+	return nil
+}
+
+func (s *Store) reset() {
+	s.users = map[int64]*model.User{}
+	s.mazes = map[int64]*model.Maze{}
+	s.solutions = map[int64]map[string][]string{}
+}
@@ -0,0 +1,61 @@
+package memstore
+
+import (
+	"github.com/egurnov/maze-api/maze-api/model"
+)
+
+var _ model.UserStore = &UserStore{&Store{}}
+
+type UserStore struct{ *Store }
+
+func (s *UserStore) GetByID(id int64) (*model.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return copyUser(user), nil
+}
+
+func (s *UserStore) GetByUsername(username string) (*model.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, user := range s.users {
+		if user.Username == username {
+			return copyUser(user), nil
+		}
+	}
+	return nil, model.ErrNotFound
+}
+
+func (s *UserStore) Create(user *model.User) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, u := range s.users {
+		if u.Username == user.Username {
+			return 0, model.ErrUsernameAlreadyUsed
+		}
+	}
+
+	s.lastUserID++
+	s.users[s.lastUserID] = &model.User{
+		ID:           s.lastUserID,
+		Username:     user.Username,
+		PasswordHash: user.PasswordHash,
+	}
+
+	return s.lastUserID, nil
+}
+
+// copyUser returns only the fields a persistent store would return, so callers can't modify stored data.
+func copyUser(user *model.User) *model.User {
+	return &model.User{
+		ID:           user.ID,
+		Username:     user.Username,
+		PasswordHash: user.PasswordHash,
+	}
+}
@@ -0,0 +1,54 @@
+package memstore_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/egurnov/maze-api/maze-api/memstore"
+	"github.com/egurnov/maze-api/maze-api/model"
+)
+
+var _ = Describe("UserStore", func() {
+	var s *memstore.UserStore
+
+	BeforeEach(func() {
+		s = &memstore.UserStore{Store: memstore.New()}
+	})
+
+	AfterEach(func() {
+		Expect(s.Wipe()).To(Succeed())
+		Expect(s.Close()).To(Succeed())
+	})
+
+	Specify("full flow", func() {
+
+		user := &model.User{
+			Username:     "me@example.com",
+			PasswordHash: "passw0rd",
+		}
+
+		id, err := s.Create(user)
+		Expect(err).ToNot(HaveOccurred())
+		user.ID = id
+
+		_, err = s.Create(&model.User{
+			Username:     "me@example.com",
+			PasswordHash: "passw0rd",
+		})
+		Expect(err).To(MatchError(model.ErrUsernameAlreadyUsed))
+
+		found, err := s.GetByID(user.ID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(Equal(user))
+
+		found, err = s.GetByUsername(user.Username)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(Equal(user))
+
+		_, err = s.GetByID(user.ID + 1)
+		Expect(err).To(MatchError(model.ErrNotFound))
+
+		_, err = s.GetByUsername("nobody@example.com")
+		Expect(err).To(MatchError(model.ErrNotFound))
+	})
+})
@@ -0,0 +1,37 @@
+package model
+
+import "strconv"
+
+// ParseA1 parses a cell in A1 notation, column letters followed by the row number, into 0-based row and column.
+func ParseA1(s string) (row, col int, err error) {
+	i := 0
+	for ; i < len(s) && 'A' <= s[i] && s[i] <= 'Z'; i++ {
+		col = int(s[i]-'A'+1) + col*int('Z'-'A'+1)
+	}
+	if i == 0 {
+		return 0, 0, ErrInvalidInput
+	}
+	for ; i < len(s) && '0' <= s[i] && s[i] <= '9'; i++ {
+		row = int(s[i]-'0') + row*int('9'-'0'+1)
+	}
+	if i != len(s) {
+		return 0, 0, ErrInvalidInput
+	}
+	return row - 1, col - 1, nil
+}
+
+// FormatA1 returns the cell at the 0-based row and column in A1 notation.
+func FormatA1(row, col int) string {
+	res := ""
+	col++
+	for ; col > 0; col /= int('Z' - 'A' + 1) {
+		if col%int('Z'-'A'+1) == 0 {
+			res = "Z" + res
+			col -= 26
+		} else {
+			res = string(byte('A'+col%int('Z'-'A'+1)-1)) + res
+		}
+
+	}
+	return res + strconv.Itoa(row+1)
+}
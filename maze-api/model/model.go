@@ -3,6 +3,8 @@ package model
 import (
 	"context"
 	"errors"
+	"math/big"
+	"time"
 )
 
 type User struct {
@@ -24,10 +26,77 @@ type Maze struct {
 	Entrance string
 	Walls    []string
 
+	// Exits are border cells, only set in ExitModeExplicit. Otherwise every open cell in the last row is an exit.
+	ExitMode string // one of ExitMode* constants, ExitModeSingle if empty
+	Exits    []string
+
+	// Costs of entering open cells, 1 if not listed
+	Costs map[string]int
+
+	// Movement is used to validate the exits and to solve the maze unless the solver is given another one
+	Movement string // one of Movement* constants, MovementOrthogonal if empty
+
+	// Version starts at 1 and is incremented by every update, solutions are only valid for the version they were found for
+	Version int64
+
+	CreatedAt time.Time
+
 	// Foreign key
 	UserID int64 `json:"-"`
 }
 
+const (
+	MovementOrthogonal  = "4"      // up, down, left and right
+	MovementDiagonal    = "8"      // also diagonally, but not past the corner of a wall
+	MovementDiagonalCut = "8-cut"  // also diagonally, cutting corners of walls
+	MovementKnight      = "knight" // chess knight jumps, walls in between don't matter
+)
+
+const (
+	ExitModeSingle   = "single-exit" // exactly one cell in the last row is reachable
+	ExitModeAny      = "any-exit"    // at least one cell in the last row is reachable
+	ExitModeExplicit = "explicit"    // at least one of the declared exits is reachable
+)
+
+const (
+	MazeSortID        = "id"
+	MazeSortCreatedAt = "createdAt"
+	MazeSortGridSize  = "gridSize" // rows*cols
+)
+
+// MazeQuery selects a page of mazes belonging to one user. Zero values mean no filtering.
+type MazeQuery struct {
+	UserID int64
+
+	MinRows, MaxRows int
+	MinCols, MaxCols int
+
+	CreatedAfter  time.Time // inclusive
+	CreatedBefore time.Time // exclusive
+
+	SortBy string // one of MazeSort* constants, MazeSortID by default
+	Desc   bool
+
+	After        *MazeCursor // continue after this position
+	Limit        int
+	WithoutWalls bool
+}
+
+// MazeCursor is a position in a sorted list of mazes.
+type MazeCursor struct {
+	SortBy    string
+	Desc      bool
+	ID        int64
+	CreatedAt time.Time
+	GridSize  int
+}
+
+type MazePage struct {
+	Mazes []*Maze
+	Total int64       // number of mazes matching the filters, regardless of the cursor and limit
+	Next  *MazeCursor // nil if this is the last page
+}
+
 type CustomClaims struct {
 	UserID int64 `json:"user_id"`
 }
@@ -45,19 +114,161 @@ type UserService interface {
 	Create(*User) (int64, error)
 }
 
+// MazeStore may return walls in a different order and spelling than they were given in, the SQL stores return them
+// in row-major order spelled like FormatA1. MazeService normalizes walls that way before storing them,
+// so every store returns them as they were given.
 type MazeStore interface {
 	GetByID(id, userId int64) (*Maze, error)
 	GetAll(userId int64) ([]*Maze, error)
+	// List returns up to q.Limit mazes and the total number of mazes matching the filters.
+	List(q *MazeQuery) ([]*Maze, int64, error)
 	Create(*Maze) (int64, error)
+	// CreateMany stores all mazes or none of them and returns their IDs in the same order.
+	CreateMany([]*Maze) ([]int64, error)
+	// Update replaces the maze. With a non-zero Version the maze is only replaced if it is still at that version,
+	// ErrConflict is returned otherwise.
+	Update(*Maze) error
+	Delete(id, userId int64) error
 	Close() error
 }
 
+type SolveOptions struct {
+	Steps      string
+	Movement   string // Overrides the movement of the maze if set
+	BestEffort bool   // Return the longest path found before the time limit, steps=max only
+	Workers    int    // Goroutines searching for the longest path, one if not set
+}
+
+// Solution is a path from the entrance to an exit. Cost is the sum of the costs of all cells after the entrance,
+// the number of steps if the maze has no costs.
+type Solution struct {
+	Path    []string
+	Cost    int
+	Optimal bool // The path is proven to be the shortest, the longest or the cheapest one
+}
+
+// ShortestPaths are the shortest paths of a maze. Total counts all of them, including those left out of Paths.
+type ShortestPaths struct {
+	Paths [][]string
+	Cost  int // Cost of the first path
+	Total *big.Int
+}
+
+// MazeStats describe the paths from the entrance to the exits.
+type MazeStats struct {
+	ShortestPathLength int // Number of cells, 0 if there is no path
+	ShortestPaths      *big.Int
+	SimplePaths        int64 // Paths not visiting any cell twice and ending at the first exit they reach
+}
+
+// MazeAnalysis describes the structure of a maze. Paths and reachability follow the solvers: paths end at the first
+// exit they reach, so cells behind exits are unreachable.
+type MazeAnalysis struct {
+	ReachableCells     int      // Open cells reachable from the entrance, exits included
+	UnreachableRegions int      // Connected groups of open cells that can't be reached
+	UnreachableCells   int      // Open cells in those regions
+	DeadEnds           int      // Reachable cells other than the entrance and exits with a single move
+	BranchingFactor    float64  // Average number of moves from reachable cells, not counting the move back
+	Chokepoints        []string // Cells every path from the entrance to an exit goes through, in the order of the paths
+	ShortestPathLength int      // Number of cells, 0 if there is no path
+	LongestPathLength  int      // Number of cells of the longest path found, 0 if there is no path
+	LongestPathOptimal bool     // The longest path search finished in time
+	Difficulty         int      // From 0 for mazes without a solution to 100
+}
+
+// PathVerification tells if a path submitted by a player is a solution of the maze: it starts at the entrance,
+// every step is a move allowed in the maze, it doesn't visit any cell twice and ends at the first exit it reaches.
+type PathVerification struct {
+	Valid              bool
+	Length             int // Number of cells
+	Cost               int // Only set for valid paths
+	ShortestPathLength int // 0 if the maze has no solution
+	ExtraSteps         int // Steps more than the shortest path, only set for valid paths
+	Errors             []PathError
+}
+
+// PathError is a problem with the cell at Index of the path.
+type PathError struct {
+	Index   int
+	Cell    string
+	Message string
+}
+
+// SolutionStore persists computed solutions. Solutions of a maze are removed by MazeStore when the maze is updated or deleted.
+type SolutionStore interface {
+	// Get returns ErrNotFound if the solution has not been saved for this version of the maze.
+	Get(mazeID, mazeVersion int64, steps string) ([]string, error)
+	// Save returns ErrNotFound if the maze has been deleted or changed since it was at mazeVersion.
+	Save(mazeID, mazeVersion int64, steps string, path []string) error
+	Close() error
+}
+
+const (
+	RenderFormatText = "text"
+	RenderFormatPNG  = "png"
+	RenderFormatSVG  = "svg"
+
+	TextStyleASCII   = "ascii"
+	TextStyleUnicode = "unicode"
+)
+
+type RenderOptions struct {
+	Format   string
+	Solution string // Overlay the path found with these steps, none if empty
+	CellSize int    // In pixels, images only
+	Style    string // Text only
+	Labels   bool   // Draw column letters and row numbers
+}
+
 type MazeService interface {
 	GetByID(id, userId int64) (*Maze, error)
 	PrintMaze(id, userId int64) ([]byte, error)
+	RenderMaze(ctx context.Context, id, userId int64, opts *RenderOptions) ([]byte, error)
 	GetAll(userId int64) ([]*Maze, error)
+	List(q *MazeQuery) (*MazePage, error)
 	Create(*Maze) (int64, error)
-	Solve(ctx context.Context, id, userId int64, steps string) ([]string, error)
+	CreateMany([]*Maze) ([]int64, error)
+	Update(*Maze) error
+	Delete(id, userId int64) error
+	Solve(ctx context.Context, id, userId int64, opts *SolveOptions) (*Solution, error)
+	ShortestPaths(ctx context.Context, id, userId int64, movement string, limit int) (*ShortestPaths, error)
+	Stats(ctx context.Context, id, userId int64, movement string) (*MazeStats, error)
+	Analyze(ctx context.Context, id, userId int64, movement string) (*MazeAnalysis, error)
+	Verify(ctx context.Context, id, userId int64, movement string, path []string) (*PathVerification, error)
+}
+
+const (
+	JobStatusQueued   = "queued"
+	JobStatusRunning  = "running"
+	JobStatusDone     = "done"
+	JobStatusFailed   = "failed"
+	JobStatusCanceled = "canceled"
+)
+
+type SolveJob struct {
+	ID         string
+	MazeID     int64
+	UserID     int64
+	Steps      string
+	Movement   string
+	BestEffort bool
+
+	Status   string
+	Explored int64    // Number of cells visited by the solver so far
+	Path     []string // Set when done
+	Cost     int      // Set when done
+	Optimal  bool     // Set when done
+	Error    string   // Set when failed
+
+	CreatedAt  time.Time
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+type SolveJobService interface {
+	Submit(mazeID, userID int64, opts *SolveOptions) (*SolveJob, error)
+	Get(id string, userID int64) (*SolveJob, error)
+	Cancel(id string, userID int64) (*SolveJob, error)
 }
 
 type JWTService interface {
@@ -73,4 +284,7 @@ var (
 	ErrorUnauthorized      = errors.New("unauthorized")
 	ErrorNoSolution        = errors.New("no solution")
 	ErrorTimelimitReached  = errors.New("time limit reached")
+	ErrorTooManyJobs       = errors.New("too many jobs, try again later")
+	ErrorImageTooLarge     = errors.New("image too large, use a smaller cell size")
+	ErrConflict            = errors.New("the maze has been changed meanwhile, try again")
 )
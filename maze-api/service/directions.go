@@ -0,0 +1,108 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Compass names of the moves of all movements. Knight jumps are named by the two steps of the jump
+// and the one step, the longer leg first, e.g. NNE is two cells north and one east.
+var compassMoves = map[Coords]string{
+	{-1, 0}: "N", {+1, 0}: "S", {0, +1}: "E", {0, -1}: "W",
+	{-1, +1}: "NE", {-1, -1}: "NW", {+1, +1}: "SE", {+1, -1}: "SW",
+	{-2, +1}: "NNE", {-1, +2}: "ENE", {+1, +2}: "ESE", {+2, +1}: "SSE",
+	{+2, -1}: "SSW", {+1, -2}: "WSW", {-1, -2}: "WNW", {-2, -1}: "NNW",
+}
+
+// Directions relative to the printed grid use Up, Down, Left and Right instead of the compass.
+var (
+	compassToRelative = strings.NewReplacer("N", "U", "S", "D", "E", "R", "W", "L")
+	relativeToCompass = strings.NewReplacer("U", "N", "D", "S", "R", "E", "L", "W")
+)
+
+var movesByName = func() map[string]Coords {
+	res := make(map[string]Coords, len(compassMoves))
+	for delta, name := range compassMoves {
+		res[name] = delta
+	}
+	return res
+}()
+
+// PathToDirections describes the path as moves separated by spaces, each followed by the number of times it is
+// repeated, e.g. "R1 D3 L1 D5". Moves are relative to the printed grid, or compass directions if compass is set.
+func PathToDirections(path []string, compass bool) (string, error) {
+	var moves []string
+	var counts []int
+	var prev Coords
+	for i, a1 := range path {
+		cur, err := A1ToCoords(a1)
+		if err != nil {
+			return "", err
+		}
+		if i > 0 {
+			name, ok := compassMoves[Coords{cur.Row - prev.Row, cur.Col - prev.Col}]
+			if !ok {
+				return "", fmt.Errorf("no direction from %s to %s", path[i-1], a1)
+			}
+			if !compass {
+				name = compassToRelative.Replace(name)
+			}
+			if len(moves) > 0 && moves[len(moves)-1] == name {
+				counts[len(counts)-1]++
+			} else {
+				moves = append(moves, name)
+				counts = append(counts, 1)
+			}
+		}
+		prev = cur
+	}
+
+	res := make([]string, len(moves))
+	for i := range moves {
+		res[i] = moves[i] + strconv.Itoa(counts[i])
+	}
+	return strings.Join(res, " "), nil
+}
+
+// DirectionsToPath follows the directions from the start cell. It takes both the relative and the compass
+// directions written by PathToDirections, the number of repetitions is 1 if it is left out.
+// Moves aren't checked against the maze, only that the path stays below and to the right of A1
+// and has at most maxLen cells, a path can't visit more cells than the maze has anyway.
+func DirectionsToPath(start string, directions string, maxLen int) ([]string, error) {
+	cur, err := A1ToCoords(start)
+	if err != nil {
+		return nil, err
+	}
+
+	path := []string{start}
+	for _, token := range strings.Fields(directions) {
+		i := strings.IndexAny(token, "0123456789")
+		name, count := token, 1
+		if i >= 0 {
+			name = token[:i]
+			count, err = strconv.Atoi(token[i:])
+			if err != nil || count < 1 {
+				return nil, errors.New("invalid direction: " + token)
+			}
+		}
+		delta, ok := movesByName[relativeToCompass.Replace(strings.ToUpper(name))]
+		if !ok {
+			return nil, errors.New("invalid direction: " + token)
+		}
+
+		if count > maxLen-len(path) {
+			return nil, fmt.Errorf("the path is longer than %d cells", maxLen)
+		}
+		for ; count > 0; count-- {
+			cur = Coords{cur.Row + delta.Row, cur.Col + delta.Col}
+			if cur.Row < 0 || cur.Col < 0 {
+				return nil, errors.New("the path leaves the grid at " + token)
+			}
+			path = append(path, CoordsToA1(cur))
+		}
+	}
+
+	return path, nil
+}
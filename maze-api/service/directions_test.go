@@ -0,0 +1,122 @@
+package service_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/egurnov/maze-api/maze-api/service"
+)
+
+func TestPathToDirections(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		path     []string
+		relative string
+		compass  string
+	}{
+		{
+			desc: "single cell",
+			path: []string{"A1"},
+		},
+		{
+			desc:     "orthogonal",
+			path:     []string{"A1", "B1", "B2", "B3", "B4", "A4", "A5", "A6"},
+			relative: "R1 D3 L1 D2",
+			compass:  "E1 S3 W1 S2",
+		},
+		{
+			desc:     "diagonal",
+			path:     []string{"C3", "B2", "A1", "B2", "A3"},
+			relative: "UL2 DR1 DL1",
+			compass:  "NW2 SE1 SW1",
+		},
+		{
+			desc:     "knight",
+			path:     []string{"A1", "B3", "D4", "B5"},
+			relative: "DDR1 RDR1 LDL1",
+			compass:  "SSE1 ESE1 WSW1",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			g := NewWithT(t)
+
+			relative, err := service.PathToDirections(tC.path, false)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(relative).To(Equal(tC.relative))
+			compass, err := service.PathToDirections(tC.path, true)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(compass).To(Equal(tC.compass))
+
+			for _, directions := range []string{relative, compass} {
+				path, err := service.DirectionsToPath(tC.path[0], directions, 100)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(path).To(Equal(tC.path))
+			}
+		})
+	}
+
+	_, err := service.PathToDirections([]string{"A1", "C1"}, false)
+	NewWithT(t).Expect(err).To(MatchError("no direction from A1 to C1"))
+}
+
+func TestDirectionsToPath(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		directions string
+		maxLen     int
+		exp        []string
+		expErr     string
+	}{
+		{
+			desc:       "count left out",
+			directions: "d d r",
+			maxLen:     10,
+			exp:        []string{"A1", "A2", "A3", "B3"},
+		},
+		{
+			desc:       "empty",
+			directions: " ",
+			maxLen:     10,
+			exp:        []string{"A1"},
+		},
+		{
+			desc:       "invalid direction",
+			directions: "D1 Q2",
+			maxLen:     10,
+			expErr:     "invalid direction: Q2",
+		},
+		{
+			desc:       "invalid count",
+			directions: "D0",
+			maxLen:     10,
+			expErr:     "invalid direction: D0",
+		},
+		{
+			desc:       "too long",
+			directions: "D2 R2",
+			maxLen:     4,
+			expErr:     "the path is longer than 4 cells",
+		},
+		{
+			desc:       "off the grid",
+			directions: "R1 U1",
+			maxLen:     10,
+			expErr:     "the path leaves the grid at U1",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			g := NewWithT(t)
+
+			path, err := service.DirectionsToPath("A1", tC.directions, tC.maxLen)
+			if tC.expErr != "" {
+				g.Expect(err).To(MatchError(tC.expErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(path).To(Equal(tC.exp))
+		})
+	}
+}
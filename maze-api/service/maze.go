@@ -9,6 +9,11 @@ import (
 
 type MazeService struct {
 	Store model.MazeStore
+
+	// Solutions are looked up in the cache, then in the solution store, and only then computed.
+	// Both are optional.
+	Cache     *SolutionCache
+	Solutions model.SolutionStore
 }
 
 var _ model.MazeService = &MazeService{}
@@ -38,15 +43,218 @@ func (s *MazeService) GetAll(userId int64) ([]*model.Maze, error) {
 	return s.Store.GetAll(userId)
 }
 
+// List fetches one extra maze to find out if there is a next page. Zero limit means all mazes.
+func (s *MazeService) List(q *model.MazeQuery) (*model.MazePage, error) {
+	if q.SortBy == "" {
+		q.SortBy = model.MazeSortID
+	}
+	if q.After != nil && (q.After.SortBy != q.SortBy || q.After.Desc != q.Desc) {
+		return nil, model.ErrInvalidInput
+	}
+
+	storeQuery := *q
+	if q.Limit > 0 {
+		storeQuery.Limit++
+	}
+	mazes, total, err := s.Store.List(&storeQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &model.MazePage{Mazes: mazes, Total: total}
+	if q.Limit > 0 && len(mazes) > q.Limit {
+		page.Mazes = mazes[:q.Limit]
+		last := page.Mazes[len(page.Mazes)-1]
+		page.Next = &model.MazeCursor{
+			SortBy:    q.SortBy,
+			Desc:      q.Desc,
+			ID:        last.ID,
+			CreatedAt: last.CreatedAt,
+			GridSize:  last.Rows * last.Cols,
+		}
+	}
+
+	return page, nil
+}
+
+// Create, CreateMany and Update normalize the walls of the mazes before storing them, see normalizeWalls.
 func (s *MazeService) Create(maze *model.Maze) (int64, error) {
+	if err := normalizeWalls(maze); err != nil {
+		return 0, err
+	}
 	return s.Store.Create(maze)
 }
 
-func (s *MazeService) Solve(ctx context.Context, id, userId int64, steps string) ([]string, error) {
+func (s *MazeService) CreateMany(mazes []*model.Maze) ([]int64, error) {
+	for _, maze := range mazes {
+		if err := normalizeWalls(maze); err != nil {
+			return nil, err
+		}
+	}
+	return s.Store.CreateMany(mazes)
+}
+
+// Update and Delete also remove cached solutions, the store removes persisted ones.
+func (s *MazeService) Update(maze *model.Maze) error {
+	if err := normalizeWalls(maze); err != nil {
+		return err
+	}
+	defer s.Cache.Invalidate(maze.ID)
+	return s.Store.Update(maze)
+}
+
+func (s *MazeService) Delete(id, userId int64) error {
+	defer s.Cache.Invalidate(id)
+	return s.Store.Delete(id, userId)
+}
+
+// Solve returns the path found with the given options and its cost. Only paths are cached, costs are cheap to compute.
+func (s *MazeService) Solve(ctx context.Context, id, userId int64, opts *model.SolveOptions) (*model.Solution, error) {
+	maze, err := s.Store.GetByID(id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	path, optimal, err := s.solvePath(ctx, maze, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	cost, err := PathCost(maze, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Solution{Path: path, Cost: cost, Optimal: optimal}, nil
+}
+
+// ShortestPaths, Stats and Analyze aren't cached, they are meant for occasional checks of maze quality.
+func (s *MazeService) ShortestPaths(ctx context.Context, id, userId int64, movement string, limit int) (*model.ShortestPaths, error) {
+	maze, err := s.Store.GetByID(id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := SolveAllMin(ctx, withMovement(maze, movement), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Cost, err = PathCost(maze, res.Paths[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *MazeService) Stats(ctx context.Context, id, userId int64, movement string) (*model.MazeStats, error) {
+	maze, err := s.Store.GetByID(id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return Stats(ctx, withMovement(maze, movement))
+}
+
+func (s *MazeService) Analyze(ctx context.Context, id, userId int64, movement string) (*model.MazeAnalysis, error) {
+	maze, err := s.Store.GetByID(id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return Analyze(ctx, withMovement(maze, movement))
+}
+
+// Verify checks a path submitted by a player and compares it to the shortest path, which is cached as usual.
+func (s *MazeService) Verify(ctx context.Context, id, userId int64, movement string, path []string) (*model.PathVerification, error) {
 	maze, err := s.Store.GetByID(id, userId)
 	if err != nil {
 		return nil, err
 	}
 
-	return Solve(ctx, maze.Rows, maze.Cols, maze.Entrance, maze.Walls, steps)
+	res, err := VerifyPath(withMovement(maze, movement), path)
+	if err != nil {
+		return nil, err
+	}
+
+	shortest, _, err := s.solvePath(ctx, maze, &model.SolveOptions{Steps: StepsMin, Movement: movement})
+	if err == model.ErrorNoSolution {
+		return res, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	res.ShortestPathLength = len(shortest)
+	if res.Valid {
+		res.ExtraSteps = res.Length - res.ShortestPathLength
+	}
+
+	return res, nil
+}
+
+// withMovement returns a copy of the maze with the movement overridden, if it is set.
+func withMovement(maze *model.Maze, movement string) *model.Maze {
+	res := *maze
+	if movement != "" {
+		res.Movement = movement
+	}
+	return &res
+}
+
+// solvePath tells if the path is optimal, only the best effort longest path may be not.
+// Paths that aren't optimal are neither cached nor saved.
+func (s *MazeService) solvePath(ctx context.Context, maze *model.Maze, opts *model.SolveOptions) ([]string, bool, error) {
+	key := solutionKey(opts.Steps, withMovement(maze, opts.Movement).Movement)
+
+	if path, ok := s.Cache.Get(maze.ID, maze.Version, key); ok {
+		return path, true, nil
+	}
+	if s.Solutions != nil {
+		path, err := s.Solutions.Get(maze.ID, maze.Version, key)
+		if err == nil {
+			s.Cache.Add(maze.ID, maze.Version, key, path)
+			return path, true, nil
+		}
+		if err != model.ErrNotFound {
+			return nil, false, err
+		}
+	}
+
+	path, optimal, err := SolveWith(ctx, maze, opts)
+	if err != nil {
+		return nil, false, err
+	}
+	if !optimal {
+		return path, false, nil
+	}
+
+	return path, true, s.saveSolution(maze, key, path)
+}
+
+// solutionKey identifies solutions of a maze in the cache and in the solution store.
+// Orthogonal movement is left out, so solutions saved before movements were introduced are still found.
+func solutionKey(steps, movement string) string {
+	if movement == "" || movement == model.MovementOrthogonal {
+		return steps
+	}
+	return steps + "/" + movement
+}
+
+// saveSolution skips the solution if the maze has been changed or deleted while it was being solved.
+// The solution store checks the version of the maze when saving, the cache only returns solutions
+// of the version they are looked up for, so a stale solution is never served even if it is added after an update.
+func (s *MazeService) saveSolution(maze *model.Maze, key string, path []string) error {
+	if s.Solutions != nil {
+		err := s.Solutions.Save(maze.ID, maze.Version, key, path)
+		if err == model.ErrNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	s.Cache.Add(maze.ID, maze.Version, key, path)
+
+	return nil
 }
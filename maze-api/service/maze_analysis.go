@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/egurnov/maze-api/maze-api/model"
+)
+
+// The longest path is only searched for this long, the rest of the analysis takes linear time.
+const analysisLongestPathTimeout = time.Second
+
+// Analyze describes the structure of the maze. The longest path search is bounded by analysisLongestPathTimeout,
+// the longest path found by then is reported.
+func Analyze(ctx context.Context, m *model.Maze) (*model.MazeAnalysis, error) {
+	maze, mv, start, isExit, err := prepareMaze(m)
+	if err != nil {
+		return nil, err
+	}
+
+	g := newCellGraph(maze, mv, start, isExit)
+	res := &model.MazeAnalysis{ReachableCells: len(g.cells)}
+	res.UnreachableRegions, res.UnreachableCells = unreachableRegions(maze, mv, g)
+
+	moves, movesFrom := 0, 0
+	for v := range g.cells {
+		if g.exit[v] {
+			continue
+		}
+		forward := len(g.adj[v])
+		if v > 0 {
+			forward-- // The move back
+			if forward == 0 {
+				res.DeadEnds++
+			}
+		}
+		moves += forward
+		movesFrom++
+	}
+	if movesFrom > 0 {
+		res.BranchingFactor = float64(moves) / float64(movesFrom)
+	}
+
+	shortest, err := solveMin(ctx, maze, mv, start, isExit)
+	if err == model.ErrorNoSolution {
+		return res, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	res.ShortestPathLength = len(shortest)
+
+	longestCtx, cancel := context.WithTimeout(ctx, analysisLongestPathTimeout)
+	defer cancel()
+	longest, err := solveMax(longestCtx, maze, mv, start, isExit, 1)
+	switch {
+	case err == nil:
+		res.LongestPathOptimal = true
+	case err != model.ErrorTimelimitReached:
+		return nil, err
+	case ctx.Err() != nil:
+		// The whole analysis ran out of time, not just the longest path search
+		return nil, model.ErrorTimelimitReached
+	}
+	res.LongestPathLength = len(longest)
+	if res.LongestPathLength < res.ShortestPathLength {
+		res.LongestPathLength = res.ShortestPathLength
+	}
+
+	// Every path goes through all chokepoints, so the shortest one lists them in order
+	index := make(map[Coords]int, len(g.cells))
+	for v, c := range g.cells {
+		index[c] = v
+	}
+	chokepoint := g.chokepoints()
+	for _, c := range shortest {
+		if chokepoint[index[c]] {
+			res.Chokepoints = append(res.Chokepoints, CoordsToA1(c))
+		}
+	}
+
+	res.Difficulty = difficulty(g, res, shortest, index)
+
+	return res, nil
+}
+
+// unreachableRegions counts the connected groups of open cells outside of the graph and the cells in them.
+func unreachableRegions(maze [][]bool, mv *movement, g *cellGraph) (regions, cells int) {
+	seen := make([][]bool, len(maze))
+	for i := range seen {
+		seen[i] = make([]bool, len(maze[0]))
+	}
+	for _, c := range g.cells {
+		seen[c.Row][c.Col] = true
+	}
+
+	for i := range maze {
+		for j := range maze[i] {
+			if maze[i][j] || seen[i][j] {
+				continue
+			}
+
+			// Non-recursive flood fill, movements are symmetric
+			regions++
+			seen[i][j] = true
+			st := []Coords{{i, j}}
+			for len(st) > 0 {
+				cur := st[len(st)-1]
+				st = st[:len(st)-1]
+				cells++
+				for _, delta := range mv.deltas {
+					next := Coords{cur.Row + delta.Row, cur.Col + delta.Col}
+					if mv.canMove(maze, cur, delta) && !seen[next.Row][next.Col] {
+						seen[next.Row][next.Col] = true
+						st = append(st, next)
+					}
+				}
+			}
+		}
+	}
+
+	return regions, cells
+}
+
+// chokepoints marks the cells other than the entrance and exits all paths to exits go through.
+// Those are articulation points found by a non-recursive Tarjan's algorithm from the entrance. Exits aren't passed
+// through, so they are left out of it, the cells next to them stand for them instead. A cell is a chokepoint if all
+// cells next to exits are either the cell itself or in subtrees it cuts off from the entrance.
+func (g *cellGraph) chokepoints() []bool {
+	n := len(g.cells)
+	disc := make([]int, n)
+	low := make([]int, n)
+	nearExits := make([]int, n) // Cells next to exits in the subtree
+	cutOff := make([]int, n)    // Cells next to exits in the subtrees cut off by the cell
+
+	type frame struct {
+		v, parent int
+		i         int // Index of the next move
+	}
+	tick := 1
+	disc[0], low[0] = tick, tick
+	frames := []frame{{v: 0, parent: -1}}
+	for len(frames) > 0 {
+		f := &frames[len(frames)-1]
+		if f.i < len(g.adj[f.v]) {
+			w := g.adj[f.v][f.i]
+			f.i++
+			if g.exit[w] {
+				continue
+			}
+			if disc[w] == 0 {
+				tick++
+				disc[w], low[w] = tick, tick
+				frames = append(frames, frame{v: w, parent: f.v})
+			} else if w != f.parent && disc[w] < low[f.v] {
+				low[f.v] = disc[w]
+			}
+			continue
+		}
+
+		v := f.v
+		if g.nearExit[v] {
+			nearExits[v]++
+		}
+		frames = frames[:len(frames)-1]
+		if len(frames) == 0 {
+			break
+		}
+		p := frames[len(frames)-1].v
+		nearExits[p] += nearExits[v]
+		if low[v] < low[p] {
+			low[p] = low[v]
+		}
+		if low[v] >= disc[p] {
+			cutOff[p] += nearExits[v]
+		}
+	}
+
+	res := make([]bool, n)
+	for v := 1; v < n; v++ {
+		if g.exit[v] || disc[v] == 0 {
+			continue
+		}
+		own := 0
+		if g.nearExit[v] {
+			own = 1
+		}
+		res[v] = nearExits[0] > 0 && nearExits[0] == cutOff[v]+own
+	}
+	return res
+}
+
+// difficulty averages three shares from 0 to 1: how much of the reachable maze the shortest path covers,
+// how many of its cells offer a choice of moves, and how many cells are dead ends, relative to a half,
+// about as many as a maze of single cell corridors can have.
+func difficulty(g *cellGraph, a *model.MazeAnalysis, shortest []Coords, index map[Coords]int) int {
+	pathShare := float64(a.ShortestPathLength) / float64(a.ReachableCells)
+
+	choiceShare := 0.0
+	if len(shortest) > 1 {
+		choices := 0
+		for i, c := range shortest[:len(shortest)-1] {
+			forward := len(g.adj[index[c]])
+			if i > 0 {
+				forward--
+			}
+			if forward > 1 {
+				choices++
+			}
+		}
+		choiceShare = float64(choices) / float64(len(shortest)-1)
+	}
+
+	deadEndShare := math.Min(1, 2*float64(a.DeadEnds)/float64(a.ReachableCells))
+
+	return int(math.Round(100 * (pathShare + choiceShare + deadEndShare) / 3))
+}
@@ -0,0 +1,86 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/egurnov/maze-api/maze-api/model"
+	"github.com/egurnov/maze-api/maze-api/service"
+)
+
+func TestAnalyze(t *testing.T) {
+	t.Run("ring", func(t *testing.T) {
+		// Two ways around B2, neither cell is needed
+		res, err := service.Analyze(context.Background(), &model.Maze{Rows: 3, Cols: 3, Entrance: "A1", Walls: []string{"B2"}, ExitMode: model.ExitModeExplicit, Exits: []string{"C3"}})
+
+		g := NewWithT(t)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res).To(Equal(&model.MazeAnalysis{
+			ReachableCells:     8,
+			BranchingFactor:    8.0 / 7,
+			ShortestPathLength: 5,
+			LongestPathLength:  5,
+			LongestPathOptimal: true,
+			Difficulty:         29,
+		}))
+	})
+
+	t.Run("tree", func(t *testing.T) {
+		// |_|_|X|_|
+		// |X|_|X|_|
+		// |_|_|_|X|
+		// |_|X|_|_|
+		res, err := service.Analyze(context.Background(), &model.Maze{Rows: 4, Cols: 4, Entrance: "A1", Walls: []string{"C1", "A2", "C2", "D3", "B4"}, ExitMode: model.ExitModeExplicit, Exits: []string{"D4"}})
+
+		g := NewWithT(t)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res).To(Equal(&model.MazeAnalysis{
+			ReachableCells:     9,
+			UnreachableRegions: 1,
+			UnreachableCells:   2,
+			DeadEnds:           1,
+			BranchingFactor:    1,
+			Chokepoints:        []string{"B1", "B2", "B3", "C3", "C4"},
+			ShortestPathLength: 7,
+			LongestPathLength:  7,
+			LongestPathOptimal: true,
+			Difficulty:         39,
+		}))
+	})
+
+	t.Run("no solution", func(t *testing.T) {
+		res, err := service.Analyze(context.Background(), &model.Maze{Rows: 3, Cols: 3, Entrance: "A1", Walls: []string{"A2", "B2", "C2"}})
+
+		g := NewWithT(t)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res).To(Equal(&model.MazeAnalysis{
+			ReachableCells:     3,
+			UnreachableRegions: 1,
+			UnreachableCells:   3,
+			DeadEnds:           1,
+			BranchingFactor:    2.0 / 3,
+		}))
+	})
+
+	t.Run("longest path bounded", func(t *testing.T) {
+		maze := &model.Maze{Rows: 12, Cols: 12, Entrance: "A1", Walls: []string{"J1", "G2", "H2", "L2", "A3", "G3", "H3", "J3", "L4", "E5", "J6", "H9", "I9", "J9", "K9", "A10", "B10", "C10", "F10", "I10", "E11"}}
+		res, err := service.Analyze(context.Background(), maze)
+
+		g := NewWithT(t)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res.ShortestPathLength).To(Equal(15))
+		g.Expect(res.LongestPathOptimal).To(BeFalse())
+		g.Expect(res.LongestPathLength).To(BeNumerically(">", 50))
+	})
+
+	t.Run("timelimit", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := service.Analyze(ctx, &model.Maze{Rows: 3, Cols: 3, Entrance: "A1"})
+
+		g := NewWithT(t)
+		g.Expect(err).To(MatchError(model.ErrorTimelimitReached))
+	})
+}
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+)
+
+const (
+	AlgorithmBacktracker = "backtracker"
+	AlgorithmPrim        = "prim"
+	AlgorithmKruskal     = "kruskal"
+	AlgorithmWilson      = "wilson"
+
+	DifficultyEasy   = "easy"
+	DifficultyMedium = "medium"
+	DifficultyHard   = "hard"
+
+	MaxGeneratedGridSize = 1000 // rows and cols
+)
+
+type GenerateOptions struct {
+	Algorithm  string
+	Seed       int64  // The same seed and options always produce the same maze
+	Difficulty string // Defaults to medium
+}
+
+// GenerateMaze creates a perfect maze, i.e. there is exactly one path between any two open cells.
+// Open cells with even coordinates are the nodes of a spanning tree built by the chosen algorithm,
+// the cells between connected nodes are open too, everything else is a wall.
+// The entrance is A1, the only exit is a corridor going down to the last row from one of the nodes.
+// The difficulty selects the exit with the shortest, median or longest path from the entrance.
+func GenerateMaze(gridSize string, opts GenerateOptions) (rows, cols int, entrance string, walls []string, err error) {
+	rows, cols, err = parseGridSize(gridSize)
+	if err != nil {
+		return 0, 0, "", nil, err
+	}
+	if rows < 2 || cols < 1 || rows > MaxGeneratedGridSize || cols > MaxGeneratedGridSize {
+		return 0, 0, "", nil, errors.New("invalid grid size value")
+	}
+
+	// Nodes are cells with even coordinates, the last row is reserved for the exit
+	g := &generator{
+		rows: (rows-2)/2 + 1,
+		cols: (cols-1)/2 + 1,
+		rnd:  rand.New(rand.NewSource(opts.Seed)),
+		open: newGrid(rows, cols),
+	}
+
+	switch opts.Algorithm {
+	case AlgorithmBacktracker:
+		g.backtracker()
+	case AlgorithmPrim:
+		g.prim()
+	case AlgorithmKruskal:
+		g.kruskal()
+	case AlgorithmWilson:
+		g.wilson()
+	default:
+		return 0, 0, "", nil, errors.New("invalid algorithm: " + opts.Algorithm)
+	}
+
+	// Pick the exit
+	exitCol, err := g.exitCol(opts.Difficulty)
+	if err != nil {
+		return 0, 0, "", nil, err
+	}
+	for r := 2 * (g.rows - 1); r < rows; r++ {
+		g.open[r][exitCol] = true
+	}
+
+	walls = []string{}
+	for r := range g.open {
+		for c := range g.open[r] {
+			if !g.open[r][c] {
+				walls = append(walls, CoordsToA1(Coords{r, c}))
+			}
+		}
+	}
+
+	return rows, cols, "A1", walls, nil
+}
+
+// generator builds a spanning tree on a rows x cols grid of nodes and carves it into open cells.
+type generator struct {
+	rows, cols int // Number of nodes
+	rnd        *rand.Rand
+	open       [][]bool // Cells
+}
+
+var nodeDeltas = []Coords{{+1, 0}, {-1, 0}, {0, +1}, {0, -1}}
+
+func (g *generator) valid(n Coords) bool {
+	return areValid(n, g.rows, g.cols)
+}
+
+// connect opens both nodes and the cell between them.
+func (g *generator) connect(a, b Coords) {
+	g.open[2*a.Row][2*a.Col] = true
+	g.open[a.Row+b.Row][a.Col+b.Col] = true
+	g.open[2*b.Row][2*b.Col] = true
+}
+
+// backtracker is a randomized depth first search, it produces long winding corridors.
+func (g *generator) backtracker() {
+	visited := newGrid(g.rows, g.cols)
+	st := []Coords{{0, 0}}
+	visited[0][0] = true
+	g.open[0][0] = true
+
+	for len(st) > 0 {
+		cur := st[len(st)-1]
+
+		var next []Coords
+		for _, d := range nodeDeltas {
+			n := Coords{cur.Row + d.Row, cur.Col + d.Col}
+			if g.valid(n) && !visited[n.Row][n.Col] {
+				next = append(next, n)
+			}
+		}
+		if len(next) == 0 {
+			st = st[:len(st)-1]
+			continue
+		}
+
+		n := next[g.rnd.Intn(len(next))]
+		visited[n.Row][n.Col] = true
+		g.connect(cur, n)
+		st = append(st, n)
+	}
+}
+
+// prim grows the tree from a random frontier edge, it produces many short dead ends.
+func (g *generator) prim() {
+	type edge struct{ from, to Coords }
+	visited := newGrid(g.rows, g.cols)
+	var frontier []edge
+	add := func(n Coords) {
+		visited[n.Row][n.Col] = true
+		for _, d := range nodeDeltas {
+			to := Coords{n.Row + d.Row, n.Col + d.Col}
+			if g.valid(to) && !visited[to.Row][to.Col] {
+				frontier = append(frontier, edge{n, to})
+			}
+		}
+	}
+	add(Coords{0, 0})
+	g.open[0][0] = true
+
+	for len(frontier) > 0 {
+		i := g.rnd.Intn(len(frontier))
+		e := frontier[i]
+		frontier[i] = frontier[len(frontier)-1]
+		frontier = frontier[:len(frontier)-1]
+
+		if visited[e.to.Row][e.to.Col] {
+			continue
+		}
+		g.connect(e.from, e.to)
+		add(e.to)
+	}
+}
+
+// kruskal joins random edges that connect different trees of a forest.
+func (g *generator) kruskal() {
+	type edge struct{ a, b Coords }
+	var edges []edge
+	for r := 0; r < g.rows; r++ {
+		for c := 0; c < g.cols; c++ {
+			if r+1 < g.rows {
+				edges = append(edges, edge{Coords{r, c}, Coords{r + 1, c}})
+			}
+			if c+1 < g.cols {
+				edges = append(edges, edge{Coords{r, c}, Coords{r, c + 1}})
+			}
+		}
+	}
+	g.rnd.Shuffle(len(edges), func(i, j int) { edges[i], edges[j] = edges[j], edges[i] })
+
+	// Disjoint set with path halving
+	parent := make([]int, g.rows*g.cols)
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+
+	g.open[0][0] = true // Single node grids have no edges
+	for _, e := range edges {
+		a, b := find(e.a.Row*g.cols+e.a.Col), find(e.b.Row*g.cols+e.b.Col)
+		if a != b {
+			parent[a] = b
+			g.connect(e.a, e.b)
+		}
+	}
+}
+
+// wilson adds loop-erased random walks to the tree, which makes it a uniformly random spanning tree.
+func (g *generator) wilson() {
+	inTree := newGrid(g.rows, g.cols)
+	inTree[0][0] = true
+	g.open[0][0] = true
+	next := make([][]int, g.rows) // Index in nodeDeltas of the last move out of every node of the current walk
+	for i := range next {
+		next[i] = make([]int, g.cols)
+	}
+
+	for r := 0; r < g.rows; r++ {
+		for c := 0; c < g.cols; c++ {
+			// Walk until the tree is hit, later moves out of a node overwrite the earlier ones, which erases loops
+			for cur := (Coords{r, c}); !inTree[cur.Row][cur.Col]; {
+				i := g.rnd.Intn(len(nodeDeltas))
+				n := Coords{cur.Row + nodeDeltas[i].Row, cur.Col + nodeDeltas[i].Col}
+				if !g.valid(n) {
+					continue
+				}
+				next[cur.Row][cur.Col] = i
+				cur = n
+			}
+
+			// Add the loop-erased walk to the tree
+			for cur := (Coords{r, c}); !inTree[cur.Row][cur.Col]; {
+				inTree[cur.Row][cur.Col] = true
+				d := nodeDeltas[next[cur.Row][cur.Col]]
+				n := Coords{cur.Row + d.Row, cur.Col + d.Col}
+				g.connect(cur, n)
+				cur = n
+			}
+		}
+	}
+}
+
+// exitCol returns the column of the last row node to lead the exit from.
+func (g *generator) exitCol(difficulty string) (int, error) {
+	// Distances from the entrance to the nodes of the last row
+	dist := newDistances(g.open, Coords{0, 0})
+	lastRow := 2 * (g.rows - 1)
+	candidates := make([]int, g.cols)
+	for i := range candidates {
+		candidates[i] = 2 * i
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return dist[lastRow][candidates[i]] < dist[lastRow][candidates[j]]
+	})
+
+	switch difficulty {
+	case DifficultyEasy:
+		return candidates[0], nil
+	case DifficultyMedium, "":
+		return candidates[len(candidates)/2], nil
+	case DifficultyHard:
+		return candidates[len(candidates)-1], nil
+	default:
+		return 0, errors.New("invalid difficulty: " + difficulty)
+	}
+}
+
+func newGrid(rows, cols int) [][]bool {
+	res := make([][]bool, rows)
+	for i := range res {
+		res[i] = make([]bool, cols)
+	}
+	return res
+}
+
+// newDistances returns the number of steps from start to every open cell using Breadth First Search, -1 if unreachable.
+func newDistances(open [][]bool, start Coords) [][]int {
+	rows, cols := len(open), len(open[0])
+	dist := make([][]int, rows)
+	for i := range dist {
+		dist[i] = make([]int, cols)
+		for j := range dist[i] {
+			dist[i][j] = -1
+		}
+	}
+	dist[start.Row][start.Col] = 0
+
+	q := []Coords{start}
+	for i := 0; i < len(q); i++ {
+		cur := q[i]
+		for _, d := range nodeDeltas {
+			n := Coords{cur.Row + d.Row, cur.Col + d.Col}
+			if areValid(n, rows, cols) && open[n.Row][n.Col] && dist[n.Row][n.Col] < 0 {
+				dist[n.Row][n.Col] = dist[cur.Row][cur.Col] + 1
+				q = append(q, n)
+			}
+		}
+	}
+	return dist
+}
@@ -0,0 +1,94 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/egurnov/maze-api/maze-api/model"
+	"github.com/egurnov/maze-api/maze-api/service"
+)
+
+func TestGenerateMaze(t *testing.T) {
+	algorithms := []string{service.AlgorithmBacktracker, service.AlgorithmPrim, service.AlgorithmKruskal, service.AlgorithmWilson}
+
+	for _, algorithm := range algorithms {
+		t.Run(algorithm, func(t *testing.T) {
+			for _, gridSize := range []string{"2x1", "2x2", "3x3", "8x8", "9x10", "31x17", "100x100"} {
+				for seed := int64(0); seed < 5; seed++ {
+					g := NewWithT(t)
+
+					rows, cols, entrance, walls, err := service.GenerateMaze(gridSize, service.GenerateOptions{Algorithm: algorithm, Seed: seed})
+					g.Expect(err).ToNot(HaveOccurred())
+
+					_, _, _, _, err = service.ValidateMaze(gridSize, entrance, walls, model.ExitModeSingle, nil, model.MovementOrthogonal)
+					g.Expect(err).ToNot(HaveOccurred(), "%s, seed %d", gridSize, seed)
+
+					// Perfect mazes have a single path from the entrance to the exit
+					if rows*cols <= 100 {
+						minPath, err := service.Solve(context.Background(), &model.Maze{Rows: rows, Cols: cols, Entrance: entrance, Walls: walls}, service.StepsMin)
+						g.Expect(err).ToNot(HaveOccurred())
+						maxPath, err := service.Solve(context.Background(), &model.Maze{Rows: rows, Cols: cols, Entrance: entrance, Walls: walls}, service.StepsMax)
+						g.Expect(err).ToNot(HaveOccurred())
+						g.Expect(maxPath).To(Equal(minPath), "%s, seed %d", gridSize, seed)
+					}
+				}
+			}
+		})
+	}
+
+	t.Run("deterministic", func(t *testing.T) {
+		g := NewWithT(t)
+
+		for _, algorithm := range algorithms {
+			_, _, _, walls1, err := service.GenerateMaze("20x20", service.GenerateOptions{Algorithm: algorithm, Seed: 42})
+			g.Expect(err).ToNot(HaveOccurred())
+			_, _, _, walls2, err := service.GenerateMaze("20x20", service.GenerateOptions{Algorithm: algorithm, Seed: 42})
+			g.Expect(err).ToNot(HaveOccurred())
+			_, _, _, walls3, err := service.GenerateMaze("20x20", service.GenerateOptions{Algorithm: algorithm, Seed: 43})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			g.Expect(walls1).To(Equal(walls2), algorithm)
+			g.Expect(walls1).ToNot(Equal(walls3), algorithm)
+		}
+
+		_, _, _, walls, err := service.GenerateMaze("5x5", service.GenerateOptions{Algorithm: service.AlgorithmBacktracker, Seed: 1})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(walls).To(Equal([]string{"A2", "B2", "C2", "D2", "A4", "B4", "D4", "E4", "A5", "B5", "D5", "E5"}))
+	})
+
+	t.Run("difficulty", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pathLen := func(difficulty string) int {
+			rows, cols, entrance, walls, err := service.GenerateMaze("30x30", service.GenerateOptions{Algorithm: service.AlgorithmKruskal, Seed: 7, Difficulty: difficulty})
+			g.Expect(err).ToNot(HaveOccurred())
+			path, err := service.Solve(context.Background(), &model.Maze{Rows: rows, Cols: cols, Entrance: entrance, Walls: walls}, service.StepsMin)
+			g.Expect(err).ToNot(HaveOccurred())
+			return len(path)
+		}
+		easy, medium, hard := pathLen(service.DifficultyEasy), pathLen(service.DifficultyMedium), pathLen(service.DifficultyHard)
+		g.Expect(easy).To(BeNumerically("<=", medium))
+		g.Expect(medium).To(BeNumerically("<=", hard))
+		g.Expect(easy).To(BeNumerically("<", hard))
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		for _, tc := range []struct {
+			gridSize string
+			opts     service.GenerateOptions
+			expErr   string
+		}{
+			{"1x10", service.GenerateOptions{Algorithm: service.AlgorithmPrim}, "invalid grid size value"},
+			{"10x0", service.GenerateOptions{Algorithm: service.AlgorithmPrim}, "invalid grid size value"},
+			{"1001x10", service.GenerateOptions{Algorithm: service.AlgorithmPrim}, "invalid grid size value"},
+			{"10x10", service.GenerateOptions{Algorithm: "eller"}, "invalid algorithm: eller"},
+			{"10x10", service.GenerateOptions{Algorithm: service.AlgorithmPrim, Difficulty: "insane"}, "invalid difficulty: insane"},
+		} {
+			g := NewWithT(t)
+			_, _, _, _, err := service.GenerateMaze(tc.gridSize, tc.opts)
+			g.Expect(err).To(MatchError(tc.expErr))
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Cells of the text formats accepted by ParseTextMaze. O and * mark the exit and the path in solution prints,
+// they are open cells.
+var (
+	pipeCells  = map[string]textCell{"X": cellWall, "_": cellOpen, "E": cellEntrance}
+	asciiCells = map[string]textCell{"#": cellWall, ".": cellOpen, "E": cellEntrance, "O": cellOpen, "*": cellOpen}
+)
+
+// Labels of the text prints: row numbers to the left of the maze and lines of column letters above it
+var (
+	rowLabel     = regexp.MustCompile(`^\s*(\d+) `)
+	columnLabels = regexp.MustCompile(`^[A-Z ]*$`)
+)
+
+type textCell int
+
+const (
+	cellOpen textCell = iota
+	cellWall
+	cellEntrance
+)
+
+// ParseTextMaze reads a maze drawn as text, one line per row, either as the |X|_| grid printed by PrintMaze
+// or with # for walls and . for open cells. E marks the entrance, the entrance is empty if there is no E.
+// Blank lines before and after the grid are ignored, so are the row numbers and column letters of labeled prints.
+func ParseTextMaze(text string) (rows, cols int, entrance string, walls []string, err error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return 0, 0, "", nil, errors.New("empty maze")
+	}
+	lines = stripLabels(lines)
+
+	for r, line := range lines {
+		cells, err := parseTextRow(strings.TrimSpace(line))
+		if err != nil {
+			return 0, 0, "", nil, fmt.Errorf("row %d: %v", r+1, err)
+		}
+		if r == 0 {
+			cols = len(cells)
+		} else if len(cells) != cols {
+			return 0, 0, "", nil, fmt.Errorf("row %d: %d cells, the first row has %d", r+1, len(cells), cols)
+		}
+
+		for c, cell := range cells {
+			a1 := CoordsToA1(Coords{r, c})
+			switch cell {
+			case cellWall:
+				walls = append(walls, a1)
+			case cellEntrance:
+				if entrance != "" {
+					return 0, 0, "", nil, fmt.Errorf("row %d: more than one entrance", r+1)
+				}
+				entrance = a1
+			}
+		}
+	}
+
+	return len(lines), cols, entrance, walls, nil
+}
+
+// stripLabels removes the labels if all rows are numbered from 1 and only column letters are above them.
+// Other lines are returned as they are.
+func stripLabels(lines []string) []string {
+	first := len(lines)
+	for first > 0 && rowLabel.MatchString(lines[first-1]) {
+		first--
+	}
+	if first == len(lines) {
+		return lines
+	}
+	for _, line := range lines[:first] {
+		if !columnLabels.MatchString(line) {
+			return lines
+		}
+	}
+
+	res := make([]string, len(lines)-first)
+	for i, line := range lines[first:] {
+		m := rowLabel.FindStringSubmatch(line)
+		if m[1] != strconv.Itoa(i+1) {
+			return lines
+		}
+		res[i] = line[len(m[0]):]
+	}
+	return res
+}
+
+func parseTextRow(line string) ([]textCell, error) {
+	var symbols []string
+	cellTypes := asciiCells
+	if strings.HasPrefix(line, "|") {
+		if len(line) < 2 || !strings.HasSuffix(line, "|") {
+			return nil, errors.New("a row of the |X|_| grid must start and end with |")
+		}
+		symbols = strings.Split(line[1:len(line)-1], "|")
+		cellTypes = pipeCells
+	} else {
+		symbols = strings.Split(line, "")
+	}
+
+	cells := make([]textCell, len(symbols))
+	for i, symbol := range symbols {
+		cell, ok := cellTypes[symbol]
+		if !ok {
+			return nil, fmt.Errorf("invalid cell %q in column %s", symbol, colLabel(i))
+		}
+		cells[i] = cell
+	}
+	return cells, nil
+}
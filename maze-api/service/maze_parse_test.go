@@ -0,0 +1,94 @@
+package service_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/egurnov/maze-api/maze-api/service"
+)
+
+func TestParseTextMaze(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		text     string
+		rows     int
+		cols     int
+		entrance string
+		walls    []string
+		err      string
+	}{
+		{
+			desc:  "pipe grid",
+			text:  "|_|X|_|\n|_|X|_|\n|_|_|_|\n",
+			rows:  3,
+			cols:  3,
+			walls: []string{"B1", "B2"},
+		},
+		{
+			desc:     "pipe grid with entrance",
+			text:     "\n|E|X|\r\n|_|_|\r\n\n",
+			rows:     2,
+			cols:     2,
+			entrance: "A1",
+			walls:    []string{"B1"},
+		},
+		{
+			desc:     "ascii",
+			text:     "#E#\n#.#\n#.#",
+			rows:     3,
+			cols:     3,
+			entrance: "B1",
+			walls:    []string{"A1", "C1", "A2", "C2", "A3", "C3"},
+		},
+		{
+			desc:     "ascii solution",
+			text:     "E#..\n*#..\n***.\n###O\n",
+			rows:     4,
+			cols:     4,
+			entrance: "A1",
+			walls:    []string{"B1", "B2", "A4", "B4", "C4"},
+		},
+		{
+			desc:     "ascii with labels",
+			text:     "  ABCD\n1 E#..\n2 *#..\n3 ****\n4 ###O\n",
+			rows:     4,
+			cols:     4,
+			entrance: "A1",
+			walls:    []string{"B1", "B2", "A4", "B4", "C4"},
+		},
+		{
+			desc:     "ascii with two lines of column letters",
+			text:     "                             AA\n   ABCDEFGHIJKLMNOPQRSTUVWXYZAB\n 1 E" + strings.Repeat(".", 27) + "\n 2 " + strings.Repeat("#", 27) + ".\n",
+			rows:     2,
+			cols:     28,
+			entrance: "A1",
+			walls:    strings.Fields("A2 B2 C2 D2 E2 F2 G2 H2 I2 J2 K2 L2 M2 N2 O2 P2 Q2 R2 S2 T2 U2 V2 W2 X2 Y2 Z2 AA2"),
+		},
+		{desc: "row numbers only", text: "1 E.\n2 #.\n", rows: 2, cols: 2, entrance: "A1", walls: []string{"A2"}},
+		{desc: "misnumbered rows", text: "  AB\n1 E.\n3 #.\n", err: `row 1: invalid cell "A" in column A`},
+		{desc: "empty", text: "\n\n", err: "empty maze"},
+		{desc: "ragged", text: "|_|_|\n|_|\n", err: "row 2: 1 cells, the first row has 2"},
+		{desc: "unclosed", text: "|_|_\n", err: "row 1: a row of the |X|_| grid must start and end with |"},
+		{desc: "invalid cell", text: "..\n.x\n", err: `row 2: invalid cell "x" in column B`},
+		{desc: "invalid pipe cell", text: "|_|#|\n", err: `row 1: invalid cell "#" in column B`},
+		{desc: "two entrances", text: "E.\n.E\n", err: "row 2: more than one entrance"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			g := NewWithT(t)
+			rows, cols, entrance, walls, err := service.ParseTextMaze(tc.text)
+			if tc.err != "" {
+				g.Expect(err).To(MatchError(tc.err))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(rows).To(Equal(tc.rows))
+			g.Expect(cols).To(Equal(tc.cols))
+			g.Expect(entrance).To(Equal(tc.entrance))
+			g.Expect(walls).To(Equal(tc.walls))
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/egurnov/maze-api/maze-api/model"
+)
+
+// textStyle lists the glyphs of a text rendering. Every cell takes width characters.
+type textStyle struct {
+	width    int
+	open     string
+	path     string
+	entrance string
+	exit     string
+	wall     func(maze [][]bool, c Coords) string
+}
+
+var textStyles = map[string]*textStyle{
+	model.TextStyleASCII: {
+		width:    1,
+		open:     ".",
+		path:     "*",
+		entrance: "E",
+		exit:     "O",
+		wall:     func([][]bool, Coords) string { return "#" },
+	},
+	model.TextStyleUnicode: {
+		width:    2,
+		open:     "  ",
+		path:     "• ",
+		entrance: "◉ ",
+		exit:     "◎ ",
+		wall:     boxWall,
+	},
+}
+
+// Box-drawing characters indexed by a bitmask of neighbouring walls: up 1, right 2, down 4, left 8.
+var boxChars = []string{"■", "╵", "╶", "└", "╷", "│", "┌", "├", "╴", "┘", "─", "┴", "┐", "┤", "┬", "┼"}
+
+// boxWall connects the wall with the neighbouring ones. The second character continues the line to the right.
+func boxWall(maze [][]bool, c Coords) string {
+	isWall := func(r, c int) bool {
+		return areValid(Coords{r, c}, len(maze), len(maze[0])) && maze[r][c]
+	}
+
+	mask := 0
+	for i, d := range []Coords{{-1, 0}, {0, +1}, {+1, 0}, {0, -1}} {
+		if isWall(c.Row+d.Row, c.Col+d.Col) {
+			mask |= 1 << i
+		}
+	}
+	if mask&2 != 0 {
+		return boxChars[mask] + "─"
+	}
+	return boxChars[mask] + " "
+}
+
+// renderText prints the maze with one line per row. Column letters are written vertically above the maze,
+// row numbers to the left of it.
+func renderText(pic *picture, style string, labels bool) ([]byte, error) {
+	st, ok := textStyles[style]
+	if !ok {
+		return nil, model.ErrInvalidInput
+	}
+
+	rows, cols := len(pic.maze), len(pic.maze[0])
+	onPath := make(map[Coords]bool, len(pic.path))
+	for _, c := range pic.path {
+		onPath[c] = true
+	}
+
+	b := &bytes.Buffer{}
+	rowLabelWidth := 0
+	if labels {
+		rowLabelWidth = len(strconv.Itoa(rows)) + 1
+
+		// The longest label is the one of the last column
+		height := len(colLabel(cols - 1))
+		for i := 0; i < height; i++ {
+			line := &strings.Builder{}
+			line.WriteString(strings.Repeat(" ", rowLabelWidth))
+			for c := 0; c < cols; c++ {
+				label := colLabel(c)
+				letter := " "
+				if j := i - (height - len(label)); j >= 0 {
+					letter = label[j : j+1]
+				}
+				line.WriteString(letter + strings.Repeat(" ", st.width-1))
+			}
+			b.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+		}
+	}
+
+	for r := 0; r < rows; r++ {
+		line := &strings.Builder{}
+		if labels {
+			fmt.Fprintf(line, "%*d ", rowLabelWidth-1, r+1)
+		}
+		for c := 0; c < cols; c++ {
+			cell := Coords{r, c}
+			switch {
+			case cell == pic.entrance:
+				line.WriteString(st.entrance)
+			case pic.exit != nil && cell == *pic.exit:
+				line.WriteString(st.exit)
+			case pic.maze[r][c]:
+				line.WriteString(st.wall(pic.maze, cell))
+			case onPath[cell]:
+				line.WriteString(st.path)
+			default:
+				line.WriteString(st.open)
+			}
+		}
+		b.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+	}
+
+	return b.Bytes(), nil
+}
@@ -0,0 +1,102 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/egurnov/maze-api/maze-api/memstore"
+	"github.com/egurnov/maze-api/maze-api/model"
+	"github.com/egurnov/maze-api/maze-api/service"
+)
+
+func TestRenderMazeText(t *testing.T) {
+	s := &service.MazeService{Store: &memstore.MazeStore{Store: memstore.New()}}
+	id, err := s.Create(&model.Maze{Rows: 4, Cols: 4, Entrance: "A1", Walls: []string{"B1", "B2", "A4", "B4", "C4"}, UserID: 1})
+	NewWithT(t).Expect(err).ToNot(HaveOccurred())
+	ctx := context.Background()
+
+	testCases := []struct {
+		desc string
+		opts model.RenderOptions
+		exp  []string
+	}{
+		{
+			desc: "ascii",
+			opts: model.RenderOptions{Style: model.TextStyleASCII, Labels: true},
+			exp: []string{
+				"  ABCD",
+				"1 E#..",
+				"2 .#..",
+				"3 ....",
+				"4 ###O",
+			},
+		},
+		{
+			desc: "ascii with the shortest path",
+			opts: model.RenderOptions{Style: model.TextStyleASCII, Labels: true, Solution: service.StepsMin},
+			exp: []string{
+				"  ABCD",
+				"1 E#..",
+				"2 *#..",
+				"3 ****",
+				"4 ###O",
+			},
+		},
+		{
+			desc: "ascii with the longest path, no labels",
+			opts: model.RenderOptions{Style: model.TextStyleASCII, Solution: service.StepsMax},
+			exp: []string{
+				"E#**",
+				"*#**",
+				"****",
+				"###O",
+			},
+		},
+		{
+			desc: "unicode",
+			opts: model.RenderOptions{Style: model.TextStyleUnicode, Labels: true, Solution: service.StepsMin},
+			exp: []string{
+				"  A B C D",
+				"1 ◉ ╷",
+				"2 • ╵",
+				"3 • • • •",
+				"4 ╶───╴ ◎",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			g := NewWithT(t)
+
+			tc.opts.Format = model.RenderFormatText
+			res, err := s.RenderMaze(ctx, id, 1, &tc.opts)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(string(res)).To(Equal(strings.Join(tc.exp, "\n") + "\n"))
+		})
+	}
+
+	t.Run("column labels", func(t *testing.T) {
+		g := NewWithT(t)
+
+		wide, err := s.Create(&model.Maze{Rows: 10, Cols: 28, Entrance: "A1", UserID: 1})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		res, err := s.RenderMaze(ctx, wide, 1, &model.RenderOptions{Format: model.RenderFormatText, Style: model.TextStyleASCII, Labels: true})
+		g.Expect(err).ToNot(HaveOccurred())
+		lines := strings.Split(string(res), "\n")
+		g.Expect(lines[0]).To(Equal("                             AA"))
+		g.Expect(lines[1]).To(Equal("   ABCDEFGHIJKLMNOPQRSTUVWXYZAB"))
+		g.Expect(lines[2]).To(HavePrefix(" 1 E...."))
+		g.Expect(lines[11]).To(HavePrefix("10 O..."))
+	})
+
+	t.Run("invalid style", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := s.RenderMaze(ctx, id, 1, &model.RenderOptions{Format: model.RenderFormatText, Style: "emoji"})
+		g.Expect(err).To(MatchError(model.ErrInvalidInput))
+	})
+}
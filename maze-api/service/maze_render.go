@@ -0,0 +1,277 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/egurnov/maze-api/maze-api/model"
+)
+
+const (
+	DefaultCellSize = 20
+	MaxImageSize    = 4096 // Maximum width and height of images in pixels
+)
+
+// picture holds everything that is drawn: walls, entrance, exit and an optional path.
+type picture struct {
+	maze     [][]bool
+	entrance Coords
+	exit     *Coords // nil if there is no reachable exit
+	path     []Coords
+}
+
+// RenderMaze draws the maze as text or an image. Zero cell size picks the largest size up to DefaultCellSize that fits into MaxImageSize.
+func (s *MazeService) RenderMaze(ctx context.Context, id, userId int64, opts *model.RenderOptions) ([]byte, error) {
+	mazeDescr, err := s.Store.GetByID(id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	pic, err := s.newPicture(ctx, mazeDescr, opts.Solution)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Format == model.RenderFormatText {
+		return renderText(pic, opts.Style, opts.Labels)
+	}
+
+	l, err := newLayout(len(pic.maze), len(pic.maze[0]), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	switch opts.Format {
+	case model.RenderFormatPNG:
+		return renderPNG(pic, l)
+	case model.RenderFormatSVG:
+		return renderSVG(pic, l), nil
+	default:
+		return nil, model.ErrInvalidInput
+	}
+}
+
+func (s *MazeService) newPicture(ctx context.Context, m *model.Maze, solution string) (*picture, error) {
+	maze, err := makeMaze(m.Rows, m.Cols, m.Walls)
+	if err != nil {
+		return nil, err
+	}
+
+	entrance, err := A1ToCoords(m.Entrance)
+	if err != nil {
+		return nil, err
+	}
+	pic := &picture{maze: maze, entrance: entrance}
+
+	// The exit is the end of the requested path or of the shortest one
+	var path []Coords
+	if solution != "" {
+		cells, _, err := s.solvePath(ctx, m, &model.SolveOptions{Steps: solution})
+		if err != nil {
+			return nil, err
+		}
+		for _, cell := range cells {
+			c, err := A1ToCoords(cell)
+			if err != nil {
+				return nil, err
+			}
+			path = append(path, c)
+		}
+		pic.path = path
+	} else {
+		isExit, err := makeExits(m.Rows, m.Exits)
+		if err != nil {
+			return nil, err
+		}
+		mv, err := getMovement(m.Movement)
+		if err != nil {
+			return nil, err
+		}
+		path, err = solveMin(ctx, maze, mv, entrance, isExit)
+		if err != nil && err != model.ErrorNoSolution {
+			return nil, err
+		}
+	}
+	if len(path) > 0 {
+		exit := path[len(path)-1]
+		pic.exit = &exit
+	}
+
+	return pic, nil
+}
+
+// layout positions cells and labels in an image.
+type layout struct {
+	rows, cols int
+	cell       int // Cell size in pixels
+	labels     bool
+	left, top  int // Space for labels
+	rowStep    int // Only every rowStep-th row and colStep-th column is labeled, so that labels don't overlap
+	colStep    int
+	width      int
+	height     int
+}
+
+const (
+	labelCharWidth  = 7 // basicfont.Face7x13
+	labelCharHeight = 13
+	labelPadding    = 4
+)
+
+func newLayout(rows, cols int, opts *model.RenderOptions) (*layout, error) {
+	l := &layout{rows: rows, cols: cols, cell: opts.CellSize, labels: opts.Labels, rowStep: 1, colStep: 1}
+	if l.labels {
+		l.left = len(strconv.Itoa(rows))*labelCharWidth + 2*labelPadding
+		l.top = labelCharHeight + 2*labelPadding
+	}
+
+	if l.cell == 0 {
+		l.cell = DefaultCellSize
+		for l.cell > 1 && (l.left+cols*l.cell > MaxImageSize || l.top+rows*l.cell > MaxImageSize) {
+			l.cell--
+		}
+	}
+
+	l.width = l.left + cols*l.cell
+	l.height = l.top + rows*l.cell
+	if l.width > MaxImageSize || l.height > MaxImageSize {
+		return nil, model.ErrorImageTooLarge
+	}
+
+	if l.labels {
+		colLabelWidth := len(colLabel(cols-1))*labelCharWidth + labelPadding
+		l.colStep = (colLabelWidth + l.cell - 1) / l.cell
+		l.rowStep = (labelCharHeight + l.cell - 1) / l.cell
+	}
+
+	return l, nil
+}
+
+// cellRect returns the pixels of the cell.
+func (l *layout) cellRect(c Coords) image.Rectangle {
+	x, y := l.left+c.Col*l.cell, l.top+c.Row*l.cell
+	return image.Rect(x, y, x+l.cell, y+l.cell)
+}
+
+// colLabel returns the letters of the column, e.g. "AA" for the 27th one.
+func colLabel(col int) string {
+	return strings.TrimRight(CoordsToA1(Coords{Row: 0, Col: col}), "1")
+}
+
+var (
+	colorWall     = color.RGBA{0x33, 0x33, 0x33, 0xff}
+	colorOpen     = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	colorPath     = color.RGBA{0x8e, 0xca, 0xe6, 0xff}
+	colorEntrance = color.RGBA{0x2a, 0x9d, 0x8f, 0xff}
+	colorExit     = color.RGBA{0xe6, 0x39, 0x46, 0xff}
+	colorLabel    = color.RGBA{0x00, 0x00, 0x00, 0xff}
+)
+
+func renderPNG(pic *picture, l *layout) ([]byte, error) {
+	// A paletted image takes one byte per pixel
+	img := image.NewPaletted(image.Rect(0, 0, l.width, l.height),
+		color.Palette{colorOpen, colorWall, colorPath, colorEntrance, colorExit, colorLabel})
+
+	fill := func(c Coords, col color.Color) {
+		draw.Draw(img, l.cellRect(c), image.NewUniform(col), image.Point{}, draw.Src)
+	}
+	for r, row := range pic.maze {
+		for c, wall := range row {
+			if wall {
+				fill(Coords{r, c}, colorWall)
+			}
+		}
+	}
+	for _, c := range pic.path {
+		fill(c, colorPath)
+	}
+	fill(pic.entrance, colorEntrance)
+	if pic.exit != nil {
+		fill(*pic.exit, colorExit)
+	}
+
+	if l.labels {
+		d := &font.Drawer{Dst: img, Src: image.NewUniform(colorLabel), Face: basicfont.Face7x13}
+		for c := 0; c < l.cols; c += l.colStep {
+			label := colLabel(c)
+			d.Dot = fixed.P(l.left+c*l.cell+(l.cell-len(label)*labelCharWidth)/2, labelPadding+basicfont.Face7x13.Ascent)
+			d.DrawString(label)
+		}
+		for r := 0; r < l.rows; r += l.rowStep {
+			label := strconv.Itoa(r + 1)
+			d.Dot = fixed.P(l.left-labelPadding-len(label)*labelCharWidth, l.top+r*l.cell+(l.cell+basicfont.Face7x13.Ascent)/2)
+			d.DrawString(label)
+		}
+	}
+
+	b := &bytes.Buffer{}
+	err := png.Encode(b, img)
+	return b.Bytes(), err
+}
+
+func renderSVG(pic *picture, l *layout) []byte {
+	b := &bytes.Buffer{}
+	hex := func(c color.RGBA) string { return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B) }
+	rect := func(r image.Rectangle, c color.RGBA) {
+		fmt.Fprintf(b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", r.Min.X, r.Min.Y, r.Dx(), r.Dy(), hex(c))
+	}
+
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %[1]d %[2]d" shape-rendering="crispEdges">`+"\n", l.width, l.height)
+	rect(image.Rect(0, 0, l.width, l.height), colorOpen)
+
+	// Consecutive walls in a row are drawn as one rectangle
+	for r, row := range pic.maze {
+		for c := 0; c < len(row); c++ {
+			if !row[c] {
+				continue
+			}
+			start := c
+			for c+1 < len(row) && row[c+1] {
+				c++
+			}
+			rect(l.cellRect(Coords{r, start}).Union(l.cellRect(Coords{r, c})), colorWall)
+		}
+	}
+
+	rect(l.cellRect(pic.entrance), colorEntrance)
+	if pic.exit != nil {
+		rect(l.cellRect(*pic.exit), colorExit)
+	}
+
+	if len(pic.path) > 0 {
+		points := make([]string, len(pic.path))
+		for i, c := range pic.path {
+			center := l.cellRect(c).Min.Add(image.Pt(l.cell/2, l.cell/2))
+			points[i] = fmt.Sprintf("%d,%d", center.X, center.Y)
+		}
+		fmt.Fprintf(b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="%d" stroke-linecap="round" stroke-linejoin="round"/>`+"\n",
+			strings.Join(points, " "), hex(colorPath), (l.cell+2)/3)
+	}
+
+	if l.labels {
+		fmt.Fprintf(b, `<g font-family="monospace" font-size="%d" fill="%s">`+"\n", labelCharHeight-1, hex(colorLabel))
+		for c := 0; c < l.cols; c += l.colStep {
+			fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="middle">%s</text>`+"\n",
+				l.left+c*l.cell+l.cell/2, labelPadding+basicfont.Face7x13.Ascent, colLabel(c))
+		}
+		for r := 0; r < l.rows; r += l.rowStep {
+			fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="end" dominant-baseline="middle">%d</text>`+"\n",
+				l.left-labelPadding, l.top+r*l.cell+l.cell/2, r+1)
+		}
+		fmt.Fprintln(b, `</g>`)
+	}
+
+	fmt.Fprintln(b, `</svg>`)
+	return b.Bytes()
+}
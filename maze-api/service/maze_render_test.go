@@ -0,0 +1,91 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"image/png"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/egurnov/maze-api/maze-api/memstore"
+	"github.com/egurnov/maze-api/maze-api/model"
+	"github.com/egurnov/maze-api/maze-api/service"
+)
+
+func TestRenderMaze(t *testing.T) {
+	s := &service.MazeService{Store: &memstore.MazeStore{Store: memstore.New()}}
+	id, err := s.Create(&model.Maze{Rows: 4, Cols: 4, Entrance: "A1", Walls: []string{"B1", "B2", "A4", "B4", "C4"}, UserID: 1})
+	NewWithT(t).Expect(err).ToNot(HaveOccurred())
+	ctx := context.Background()
+
+	t.Run("png", func(t *testing.T) {
+		g := NewWithT(t)
+
+		b, err := s.RenderMaze(ctx, id, 1, &model.RenderOptions{Format: model.RenderFormatPNG, CellSize: 10})
+		g.Expect(err).ToNot(HaveOccurred())
+		img, err := png.Decode(bytes.NewReader(b))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(img.Bounds().Dx()).To(Equal(40))
+		g.Expect(img.Bounds().Dy()).To(Equal(40))
+
+		color := func(x, y int) [3]uint32 {
+			r, g, b, _ := img.At(x, y).RGBA()
+			return [3]uint32{r >> 8, g >> 8, b >> 8}
+		}
+		g.Expect(color(5, 5)).To(Equal([3]uint32{0x2a, 0x9d, 0x8f}))   // Entrance A1
+		g.Expect(color(15, 5)).To(Equal([3]uint32{0x33, 0x33, 0x33}))  // Wall B1
+		g.Expect(color(25, 5)).To(Equal([3]uint32{0xff, 0xff, 0xff}))  // Open C1
+		g.Expect(color(35, 35)).To(Equal([3]uint32{0xe6, 0x39, 0x46})) // Exit D4
+
+		// Solution overlay
+		b, err = s.RenderMaze(ctx, id, 1, &model.RenderOptions{Format: model.RenderFormatPNG, CellSize: 10, Solution: "min"})
+		g.Expect(err).ToNot(HaveOccurred())
+		img, err = png.Decode(bytes.NewReader(b))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(color(5, 15)).To(Equal([3]uint32{0x8e, 0xca, 0xe6})) // A2 is on the path
+		g.Expect(color(25, 5)).To(Equal([3]uint32{0xff, 0xff, 0xff})) // C1 is not
+	})
+
+	t.Run("labels", func(t *testing.T) {
+		g := NewWithT(t)
+
+		b, err := s.RenderMaze(ctx, id, 1, &model.RenderOptions{Format: model.RenderFormatPNG, CellSize: 20, Labels: true})
+		g.Expect(err).ToNot(HaveOccurred())
+		img, err := png.Decode(bytes.NewReader(b))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(img.Bounds().Dx()).To(BeNumerically(">", 80))
+		g.Expect(img.Bounds().Dy()).To(BeNumerically(">", 80))
+	})
+
+	t.Run("svg", func(t *testing.T) {
+		g := NewWithT(t)
+
+		b, err := s.RenderMaze(ctx, id, 1, &model.RenderOptions{Format: model.RenderFormatSVG, CellSize: 10, Labels: true, Solution: "max"})
+		g.Expect(err).ToNot(HaveOccurred())
+		svg := string(b)
+		g.Expect(svg).To(HavePrefix(`<svg xmlns="http://www.w3.org/2000/svg"`))
+		g.Expect(svg).To(ContainSubstring(`<polyline points="`))
+		// Every other column and row is labeled, the labels are wider than the cells
+		g.Expect(svg).To(ContainSubstring(`>C</text>`))
+		g.Expect(svg).To(ContainSubstring(`>3</text>`))
+		// B1 and B2 are separate rectangles, A4:C4 is a single one
+		g.Expect(svg).To(ContainSubstring(`width="30" height="10" fill="#333333"`))
+	})
+
+	t.Run("size", func(t *testing.T) {
+		g := NewWithT(t)
+
+		big, err := s.Create(&model.Maze{Rows: 1000, Cols: 1000, Entrance: "A1", UserID: 1})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		b, err := s.RenderMaze(ctx, big, 1, &model.RenderOptions{Format: model.RenderFormatPNG})
+		g.Expect(err).ToNot(HaveOccurred())
+		img, err := png.Decode(bytes.NewReader(b))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(img.Bounds().Dx()).To(Equal(4000))
+
+		_, err = s.RenderMaze(ctx, big, 1, &model.RenderOptions{Format: model.RenderFormatPNG, CellSize: 5})
+		g.Expect(err).To(MatchError(model.ErrorImageTooLarge))
+	})
+}
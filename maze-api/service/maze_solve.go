@@ -1,20 +1,15 @@
 package service
 
 import (
+	"container/heap"
 	"context"
 
 	"github.com/egurnov/maze-api/maze-api/model"
 )
 
-func Solve(ctx context.Context, rows, cols int, entrance string, walls []string, steps string) ([]string, error) {
-	maze, err := makeMaze(rows, cols, walls)
-	if err != nil {
-		return nil, err
-	}
-
-	// printMaze(maze) // DEBUG
-
-	start, err := A1ToCoords(entrance)
+// Solve finds a path from the entrance to one of the exits of the maze. The last cell of the path is the exit it reaches.
+func Solve(ctx context.Context, m *model.Maze, steps string) ([]string, error) {
+	maze, mv, start, isExit, err := prepareMaze(m)
 	if err != nil {
 		return nil, err
 	}
@@ -22,9 +17,16 @@ func Solve(ctx context.Context, rows, cols int, entrance string, walls []string,
 	var res []Coords
 	switch steps {
 	case StepsMin:
-		res, err = solveMin(ctx, maze, start)
+		res, err = solveMin(ctx, maze, mv, start, isExit)
 	case StepsMax:
-		res, err = solveMax(ctx, maze, start)
+		res, err = solveMax(ctx, maze, mv, start, isExit, 1)
+	case StepsCheapest:
+		var costs [][]int
+		costs, err = makeCosts(m.Rows, m.Cols, m.Costs)
+		if err != nil {
+			return nil, err
+		}
+		res, err = solveCheapest(ctx, maze, mv, costs, start, isExit)
 	default:
 		return nil, model.ErrInvalidInput
 	}
@@ -32,104 +34,78 @@ func Solve(ctx context.Context, rows, cols int, entrance string, walls []string,
 		return nil, err
 	}
 
-	resStr := make([]string, len(res))
-	for i, c := range res {
-		resStr[i] = CoordsToA1(c)
-	}
-
-	return resStr, nil
+	return pathToA1(res), nil
 }
 
-// SolveMax uses a non-recursive Depth First Search algorithm.
-// For this kind of graph there is no polinomial time solution, so exponential is the best we can do.
-// Because we manage our own stack, it also represents the current path.
-func solveMax(ctx context.Context, maze [][]bool, start Coords) ([]Coords, error) {
-	// Init
-	type StackEntry struct {
-		Coords
-		deltaI int
+// SolveWith finds a path like Solve, taking all solve options into account.
+// optimal is false only for a best effort longest path cut short by the time limit.
+func SolveWith(ctx context.Context, m *model.Maze, opts *model.SolveOptions) (path []string, optimal bool, err error) {
+	m = withMovement(m, opts.Movement)
+	switch {
+	case opts.Steps == StepsMax && opts.BestEffort:
+		return SolveMaxBestEffort(ctx, m, opts.Workers)
+	case opts.Steps == StepsMax && opts.Workers > 1:
+		path, err = SolveMaxParallel(ctx, m, opts.Workers)
+	default:
+		path, err = Solve(ctx, m, opts.Steps)
 	}
-	st := []*StackEntry{{start, 0}}
-	rows := len(maze)
-	cols := len(maze[0])
-	var res []Coords
+	return path, err == nil, err
+}
 
-	been := make([][]bool, rows)
-	for i := range been {
-		been[i] = make([]bool, cols)
+// SolveMaxParallel finds the longest path like Solve, splitting the search between the given number of goroutines.
+func SolveMaxParallel(ctx context.Context, m *model.Maze, workers int) ([]string, error) {
+	maze, mv, start, isExit, err := prepareMaze(m)
+	if err != nil {
+		return nil, err
 	}
 
-	deltas := []Coords{{+1, 0}, {-1, 0}, {0, +1}, {0, -1}}
-
-	// Main DFS loop
-dfs:
-	for len(st) > 0 {
-		// Timelimit check
-		select {
-		case <-ctx.Done():
-			return nil, model.ErrorTimelimitReached
-		default:
-		}
-
-		// Take stack top
-		cur := st[len(st)-1]
-		been[cur.Row][cur.Col] = true
-
-		// fmt.Printf(">>> Vising %v\n", cur) // DEBUG
-
-		// Check exit conditions
-		if cur.Row == rows-1 {
-			if len(st) > len(res) {
-				res = make([]Coords, len(st))
-				for i := range st {
-					res[i] = st[i].Coords
-				}
-			}
-
-			// Don't go anywhere from exit
-			cur.deltaI = len(deltas)
-		}
-
-		// Iterate directions
-		for cur.deltaI < len(deltas) {
-			delta := deltas[cur.deltaI]
-			cur.deltaI++
-
-			// fmt.Printf(">>> Trying %v\n", Coords{cur.Row + delta.Row, cur.Col + delta.Col}) // DEBUG
+	res, err := solveMax(ctx, maze, mv, start, isExit, workers)
+	if err != nil {
+		return nil, err
+	}
 
-			// If can go in this direction
-			if 0 <= cur.Row+delta.Row && cur.Row+delta.Row < rows &&
-				0 <= cur.Col+delta.Col && cur.Col+delta.Col < cols &&
-				!maze[cur.Row+delta.Row][cur.Col+delta.Col] &&
-				!been[cur.Row+delta.Row][cur.Col+delta.Col] {
+	return pathToA1(res), nil
+}
 
-				// fmt.Printf(">>> Adding %v\n", Coords{cur.Row + delta.Row, cur.Col + delta.Col}) // DEBUG
+// SolveMaxBestEffort finds the longest path like SolveMaxParallel, but when the context is done it returns the longest path
+// found so far instead of an error. If none has been found yet, it returns the shortest one.
+// optimal tells if the path is proven to be the longest one.
+func SolveMaxBestEffort(ctx context.Context, m *model.Maze, workers int) (path []string, optimal bool, err error) {
+	maze, mv, start, isExit, err := prepareMaze(m)
+	if err != nil {
+		return nil, false, err
+	}
 
-				// Add to stack
-				st = append(st, &StackEntry{
-					Coords: Coords{cur.Row + delta.Row, cur.Col + delta.Col},
-				})
-				continue dfs
+	res, err := solveMax(ctx, maze, mv, start, isExit, workers)
+	if err == model.ErrorTimelimitReached {
+		// The shortest path takes linear time, it doesn't need a time limit
+		if res == nil {
+			res, err = solveMin(context.Background(), maze, mv, start, isExit)
+			if err != nil {
+				return nil, false, err
 			}
 		}
-
-		// Pop stack
-		if cur.deltaI == len(deltas) {
-			been[cur.Row][cur.Col] = false
-			st = st[:len(st)-1]
-		}
+		return pathToA1(res), false, nil
+	}
+	if err != nil {
+		return nil, false, err
 	}
 
-	if res == nil {
-		return nil, model.ErrorNoSolution
+	return pathToA1(res), true, nil
+}
+
+func pathToA1(path []Coords) []string {
+	res := make([]string, len(path))
+	for i, c := range path {
+		res[i] = CoordsToA1(c)
 	}
-	return res, nil
+	return res
 }
 
 // SolveMin uses a non-recursive Breadth First Search algorithm. Visited cells are added to a queue and processed in order.
 // Because there are no weights in the graph, all path lenghts in the queue will be in non-decreasing order.
 // Execution time is O(number of reachable cells), in the worst case O(rows*columns).
-func solveMin(ctx context.Context, maze [][]bool, start Coords) ([]Coords, error) {
+func solveMin(ctx context.Context, maze [][]bool, mv *movement, start Coords, isExit func(Coords) bool) ([]Coords, error) {
 	// Init
 	type QueueEntry struct {
 		Coords
@@ -140,6 +116,7 @@ func solveMin(ctx context.Context, maze [][]bool, start Coords) ([]Coords, error
 	rows := len(maze)
 	cols := len(maze[0])
 	exit := -1 // Index in the queue for the exit cell
+	progress := progressFromContext(ctx)
 
 	been := make([][]bool, rows)
 	for i := range been {
@@ -159,18 +136,17 @@ bfs:
 
 		// Take the next cell from the queue
 		cur := q[i]
+		progress.visit()
 
 		// fmt.Printf(">>> Vising %v\n", cur) // DEBUG
 
 		// Iterate directions
-		for _, delta := range []Coords{{+1, 0}, {-1, 0}, {0, +1}, {0, -1}} {
+		for _, delta := range mv.deltas {
 
 			// fmt.Printf(">>> Trying %v\n", Coords{cur.Row + delta.Row, cur.Col + delta.Col}) // DEBUG
 
 			// If can go in this direction
-			if 0 <= cur.Row+delta.Row && cur.Row+delta.Row < rows &&
-				0 <= cur.Col+delta.Col && cur.Col+delta.Col < cols &&
-				!maze[cur.Row+delta.Row][cur.Col+delta.Col] &&
+			if mv.canMove(maze, cur.Coords, delta) &&
 				!been[cur.Row+delta.Row][cur.Col+delta.Col] {
 
 				// fmt.Printf(">>> Adding %v\n", Coords{cur.Row + delta.Row, cur.Col + delta.Col}) // DEBUG
@@ -185,7 +161,7 @@ bfs:
 				been[cur.Row+delta.Row][cur.Col+delta.Col] = true
 
 				// Check exit condition. The first path we find is the shortest.
-				if cur.Row+delta.Row == rows-1 {
+				if isExit(q[len(q)-1].Coords) {
 					exit = len(q) - 1
 					break bfs
 				}
@@ -204,3 +180,145 @@ bfs:
 
 	return nil, model.ErrorNoSolution
 }
+
+// SolveCheapest uses the A* algorithm. Cells are taken from a priority queue ordered by the cost to reach the cell
+// plus an estimate of the remaining cost. The estimate is the number of steps to the nearest exit, which never exceeds
+// the real cost because every cell costs at least 1. Cells that can't reach any exit are skipped.
+// Execution time is O(N*log(N)) where N is the number of reachable cells.
+func solveCheapest(ctx context.Context, maze [][]bool, mv *movement, costs [][]int, start Coords, isExit func(Coords) bool) ([]Coords, error) {
+	// Init
+	rows := len(maze)
+	cols := len(maze[0])
+	progress := progressFromContext(ctx)
+	estimate := distancesToExits(maze, mv, isExit)
+	if estimate[start.Row][start.Col] < 0 {
+		return nil, model.ErrorNoSolution
+	}
+
+	const unknown = -1
+	cost := make([][]int, rows) // Lowest known cost to reach the cell
+	prev := make([][]Coords, rows)
+	for i := range cost {
+		cost[i] = make([]int, cols)
+		prev[i] = make([]Coords, cols)
+		for j := range cost[i] {
+			cost[i][j] = unknown
+		}
+	}
+	cost[start.Row][start.Col] = 0
+
+	q := &costQueue{}
+	heap.Push(q, costQueueEntry{Coords: start, priority: estimate[start.Row][start.Col]})
+
+	// Main A* loop
+	for seq := 1; q.Len() > 0; {
+		// Timelimit check
+		select {
+		case <-ctx.Done():
+			return nil, model.ErrorTimelimitReached
+		default:
+		}
+
+		// Take the most promising cell, skipping outdated entries
+		cur := heap.Pop(q).(costQueueEntry)
+		if cur.cost != cost[cur.Row][cur.Col] {
+			continue
+		}
+		progress.visit()
+
+		// Check exit condition. With a consistent estimate the first exit we take is the cheapest.
+		if cur.Coords != start && isExit(cur.Coords) {
+			var res []Coords
+			for c := cur.Coords; c != start; c = prev[c.Row][c.Col] {
+				res = append(res, c)
+			}
+			res = append(res, start)
+			for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+				res[i], res[j] = res[j], res[i]
+			}
+			return res, nil
+		}
+
+		// Iterate directions
+		for _, delta := range mv.deltas {
+			next := Coords{cur.Row + delta.Row, cur.Col + delta.Col}
+			if !mv.canMove(maze, cur.Coords, delta) || estimate[next.Row][next.Col] < 0 {
+				continue
+			}
+
+			nextCost := cur.cost + costs[next.Row][next.Col]
+			if cost[next.Row][next.Col] != unknown && cost[next.Row][next.Col] <= nextCost {
+				continue
+			}
+			cost[next.Row][next.Col] = nextCost
+			prev[next.Row][next.Col] = cur.Coords
+			heap.Push(q, costQueueEntry{
+				Coords:   next,
+				cost:     nextCost,
+				priority: nextCost + estimate[next.Row][next.Col],
+				seq:      seq,
+			})
+			seq++
+		}
+	}
+
+	return nil, model.ErrorNoSolution
+}
+
+// distancesToExits returns the number of steps from every cell to the nearest exit, -1 if no exit is reachable.
+// It is a Breadth First Search starting from all exits at once.
+func distancesToExits(maze [][]bool, mv *movement, isExit func(Coords) bool) [][]int {
+	rows := len(maze)
+	cols := len(maze[0])
+	dist := make([][]int, rows)
+	var q []Coords
+	for r := range dist {
+		dist[r] = make([]int, cols)
+		for c := range dist[r] {
+			dist[r][c] = -1
+			if !maze[r][c] && isExit(Coords{r, c}) {
+				dist[r][c] = 0
+				q = append(q, Coords{r, c})
+			}
+		}
+	}
+
+	for i := 0; i < len(q); i++ {
+		cur := q[i]
+		for _, delta := range mv.deltas {
+			next := Coords{cur.Row + delta.Row, cur.Col + delta.Col}
+			if mv.canMove(maze, cur, delta) && dist[next.Row][next.Col] < 0 {
+				dist[next.Row][next.Col] = dist[cur.Row][cur.Col] + 1
+				q = append(q, next)
+			}
+		}
+	}
+
+	return dist
+}
+
+type costQueueEntry struct {
+	Coords
+	cost     int // Cost to reach the cell
+	priority int // Cost plus the estimate of the remaining cost
+	seq      int // Insertion order, breaks ties so that results don't depend on the heap implementation
+}
+
+// costQueue implements heap.Interface.
+type costQueue []costQueueEntry
+
+func (q costQueue) Len() int { return len(q) }
+func (q costQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority < q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+func (q costQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *costQueue) Push(x interface{}) { *q = append(*q, x.(costQueueEntry)) }
+func (q *costQueue) Pop() interface{} {
+	old := *q
+	res := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return res
+}
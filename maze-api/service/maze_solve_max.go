@@ -0,0 +1,684 @@
+package service
+
+import (
+	"context"
+	"math/bits"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/egurnov/maze-api/maze-api/model"
+)
+
+// Mazes with few paths, e.g. with corridors one cell wide, are solved faster by trying all paths than by building
+// the graph and computing bounds. A maze without loops takes the plain search about one move per cell, so it gives up
+// after this many moves plus this many per cell, the overhead on other mazes is small.
+const (
+	plainMoves        = 100
+	plainMovesPerCell = 2
+)
+
+// Mazes with up to this many reachable cells are solved with dynamic programming over subsets of cells.
+// Its table grows as 2^cells, the search is faster beyond that.
+const maxSubsetCells = 12
+
+// The parallel search splits the search tree into several subtrees per goroutine, so that they get about the same
+// amount of work, but stops splitting at this depth.
+const (
+	subtreesPerWorker = 8
+	maxSplitDepth     = 32
+)
+
+// SolveMax finds the longest path. There is no polinomial time solution for this kind of graph, so it is still
+// exponential in the worst case, but most branches of the search are cut off early:
+//   - cells that can't reach an exit any more are never entered,
+//   - the remaining cells are split into biconnected components (blocks) at articulation points. A path can only go
+//     through the blocks on the way from the current cell to an exit, all other blocks are dead ends,
+//   - a path alternates chessboard colours if the movement allows that, so a block can't give it more cells
+//     than twice the number of cells of the rarer colour,
+//   - a branch whose upper bound doesn't beat the longest path found so far is skipped,
+//   - the search stops as soon as a path as long as the upper bound for the whole maze is found.
+//
+// Mazes with few paths are solved by the plain search, small ones with dynamic programming over subsets of cells.
+// With more than one worker the search runs in that many goroutines, fewer if the context limits them with SolveCores.
+// When the context is done, the longest path found so far is returned along with the error, if there is one.
+func solveMax(ctx context.Context, maze [][]bool, mv *movement, start Coords, isExit func(Coords) bool, workers int) ([]Coords, error) {
+	// Timelimit check
+	select {
+	case <-ctx.Done():
+		return nil, model.ErrorTimelimitReached
+	default:
+	}
+
+	// Don't go anywhere from exit
+	if isExit(start) {
+		return []Coords{start}, nil
+	}
+
+	plain, done, err := searchPlain(ctx, maze, mv, start, isExit, plainMoves+plainMovesPerCell*len(maze)*len(maze[0]))
+	if done || err != nil {
+		return plain, err
+	}
+
+	g := newCellGraph(maze, mv, start, isExit)
+	var path []int
+	if len(g.cells) <= maxSubsetCells {
+		path, err = g.longestPathDP(ctx)
+	} else {
+		var release func()
+		workers, release = solveCoresFromContext(ctx).take(workers)
+		defer release()
+
+		if workers > 1 {
+			path, err = searchParallel(ctx, g, workers)
+		} else {
+			path, err = newLongestPathSearch(g).run(ctx)
+		}
+	}
+	if err == nil && path == nil {
+		return nil, model.ErrorNoSolution
+	}
+	// A search cut short may not have caught up with the plain search yet
+	if err != nil && len(path) < len(plain) {
+		return plain, err
+	}
+
+	res := make([]Coords, len(path))
+	for i, v := range path {
+		res[i] = g.cells[v]
+	}
+	return res, err
+}
+
+// searchPlain is a Depth First Search through all paths. It tells if it has finished, it stops after maxMoves moves
+// unless maxMoves is 0. The longest path found so far is returned either way.
+func searchPlain(ctx context.Context, maze [][]bool, mv *movement, start Coords, isExit func(Coords) bool, maxMoves int) ([]Coords, bool, error) {
+	// Init
+	type StackEntry struct {
+		Coords
+		deltaI int
+	}
+	st := []StackEntry{{start, 0}}
+	rows := len(maze)
+	cols := len(maze[0])
+	var res []Coords
+	progress := progressFromContext(ctx)
+	moves := 0
+
+	been := make([][]bool, rows)
+	for i := range been {
+		been[i] = make([]bool, cols)
+	}
+
+	deltas := mv.deltas
+
+	// Main DFS loop
+dfs:
+	for len(st) > 0 {
+		// Timelimit check
+		select {
+		case <-ctx.Done():
+			return res, false, model.ErrorTimelimitReached
+		default:
+		}
+
+		// Take stack top, the pointer is valid until the next push
+		cur := &st[len(st)-1]
+		if !been[cur.Row][cur.Col] {
+			been[cur.Row][cur.Col] = true
+			progress.visit()
+		}
+
+		// Check exit conditions
+		if isExit(cur.Coords) {
+			if len(st) > len(res) {
+				res = make([]Coords, len(st))
+				for i := range st {
+					res[i] = st[i].Coords
+				}
+			}
+
+			// Don't go anywhere from exit
+			cur.deltaI = len(deltas)
+		}
+
+		// Iterate directions
+		for cur.deltaI < len(deltas) {
+			delta := deltas[cur.deltaI]
+			cur.deltaI++
+
+			// If can go in this direction
+			if mv.canMove(maze, cur.Coords, delta) &&
+				!been[cur.Row+delta.Row][cur.Col+delta.Col] {
+
+				moves++
+				if moves == maxMoves {
+					return res, false, nil
+				}
+
+				// Add to stack
+				st = append(st, StackEntry{
+					Coords: Coords{cur.Row + delta.Row, cur.Col + delta.Col},
+				})
+				continue dfs
+			}
+		}
+
+		// Pop stack
+		if cur.deltaI == len(deltas) {
+			been[cur.Row][cur.Col] = false
+			st = st[:len(st)-1]
+		}
+	}
+
+	if res == nil {
+		return nil, true, model.ErrorNoSolution
+	}
+	return res, true, nil
+}
+
+// cellGraph holds the cells reachable from the entrance, numbered in the order of a Breadth First Search,
+// so the entrance is 0. Paths end at the first exit they reach, so there are no moves from exits.
+type cellGraph struct {
+	cells     []Coords
+	adj       [][]int // Moves from the cell, in the order of the movement deltas
+	exit      []bool
+	nearExit  []bool // An exit is one move away
+	color     []int  // Chessboard colour
+	bipartite bool
+}
+
+func newCellGraph(maze [][]bool, mv *movement, start Coords, isExit func(Coords) bool) *cellGraph {
+	index := make([][]int, len(maze))
+	for i := range index {
+		index[i] = make([]int, len(maze[0]))
+		for j := range index[i] {
+			index[i][j] = -1
+		}
+	}
+	index[start.Row][start.Col] = 0
+
+	g := &cellGraph{cells: []Coords{start}, bipartite: mv.bipartite}
+	for i := 0; i < len(g.cells); i++ {
+		cur := g.cells[i]
+		g.exit = append(g.exit, i > 0 && isExit(cur))
+		g.color = append(g.color, (cur.Row+cur.Col)%2)
+		g.adj = append(g.adj, nil)
+		if g.exit[i] {
+			continue
+		}
+
+		for _, delta := range mv.deltas {
+			if !mv.canMove(maze, cur, delta) {
+				continue
+			}
+			next := Coords{cur.Row + delta.Row, cur.Col + delta.Col}
+			if index[next.Row][next.Col] < 0 {
+				index[next.Row][next.Col] = len(g.cells)
+				g.cells = append(g.cells, next)
+			}
+			g.adj[i] = append(g.adj[i], index[next.Row][next.Col])
+		}
+	}
+
+	g.nearExit = make([]bool, len(g.cells))
+	for v, moves := range g.adj {
+		for _, w := range moves {
+			if g.exit[w] {
+				g.nearExit[v] = true
+			}
+		}
+	}
+
+	return g
+}
+
+// longestPathDP goes through subsets of cells containing the entrance in increasing order.
+// ends[mask] has a bit set for every cell a path visiting exactly the cells of the mask can end in.
+// It returns nil if no exit is reachable.
+func (g *cellGraph) longestPathDP(ctx context.Context) ([]int, error) {
+	progress := progressFromContext(ctx)
+	n := len(g.cells)
+	ends := make([]uint32, 1<<n)
+	ends[1] = 1
+	bestMask, bestEnd := 0, -1
+
+	for mask := 1; mask < len(ends); mask += 2 {
+		// Timelimit check
+		if mask&0xfff == 1 {
+			select {
+			case <-ctx.Done():
+				return nil, model.ErrorTimelimitReached
+			default:
+			}
+		}
+
+		if ends[mask] == 0 {
+			continue
+		}
+		progress.visit()
+
+		for v := 0; v < n; v++ {
+			if ends[mask]&(1<<v) == 0 {
+				continue
+			}
+			if g.exit[v] {
+				if bits.OnesCount(uint(mask)) > bits.OnesCount(uint(bestMask)) {
+					bestMask, bestEnd = mask, v
+				}
+				continue
+			}
+			for _, w := range g.adj[v] {
+				if mask&(1<<w) == 0 {
+					ends[mask|1<<w] |= 1 << w
+				}
+			}
+		}
+	}
+
+	if bestEnd < 0 {
+		return nil, nil
+	}
+
+	// Walk back from the exit through cells the shorter paths end in
+	res := make([]int, bits.OnesCount(uint(bestMask)))
+	mask, v := bestMask, bestEnd
+	for i := len(res) - 1; i > 0; i-- {
+		res[i] = v
+		mask ^= 1 << v
+	prev:
+		for u := 0; u < n; u++ {
+			if ends[mask]&(1<<u) == 0 || g.exit[u] {
+				continue
+			}
+			for _, w := range g.adj[u] {
+				if w == v {
+					v = u
+					break prev
+				}
+			}
+		}
+	}
+	res[0] = v
+
+	return res, nil
+}
+
+// longestPathSearch is a Depth First Search with branch and bound. It keeps scratch space for computing the bounds.
+type longestPathSearch struct {
+	g       *cellGraph
+	visited []bool
+
+	// Biconnected components of the cells left, found by the Tarjan's algorithm
+	time       int
+	disc, low  []int // Discovery time, 0 for cells not discovered yet, and the earliest cell reachable from the subtree
+	reach      []int // Upper bound for the number of cells from the current cell to this one
+	touched    []int
+	frames     []tarjanFrame
+	stack      []int
+	blocks     []block
+	blockCells []int
+}
+
+type tarjanFrame struct {
+	v, parent int
+	i         int // Index of the next move
+}
+
+// block is a biconnected component. Paths enter it through the top cell, blockCells[first:last] are the other cells.
+type block struct {
+	top         int
+	first, last int
+}
+
+func newLongestPathSearch(g *cellGraph) *longestPathSearch {
+	n := len(g.cells)
+	return &longestPathSearch{
+		g:       g,
+		visited: make([]bool, n),
+		disc:    make([]int, n),
+		low:     make([]int, n),
+		reach:   make([]int, n),
+	}
+}
+
+// run returns nil if no exit is reachable. When the context is done, it returns the longest path found so far.
+func (s *longestPathSearch) run(ctx context.Context) ([]int, error) {
+	best := s.start(ctx)
+	if best == nil {
+		return nil, nil
+	}
+	err := s.search(ctx, []int{0}, best)
+	return best.path, err
+}
+
+// start bounds the length of all paths, it returns nil if no exit is reachable.
+func (s *longestPathSearch) start(ctx context.Context) *longestPath {
+	progressFromContext(ctx).visit()
+	s.visited[0] = true
+	limit := 1 + s.bound(0) // No path can be longer
+	s.visited[0] = false
+	if limit == 1 {
+		return nil
+	}
+	return &longestPath{limit: limit}
+}
+
+// search goes through the paths starting with prefix. Branches are pruned against the longest path found so far,
+// which may be updated by other searches at the same time.
+func (s *longestPathSearch) search(ctx context.Context, prefix []int, best *longestPath) error {
+	g := s.g
+	progress := progressFromContext(ctx)
+
+	type StackEntry struct {
+		v     int
+		moves []int
+		i     int
+	}
+	for _, v := range prefix {
+		s.visited[v] = true
+	}
+	defer func() {
+		for _, v := range prefix {
+			s.visited[v] = false
+		}
+	}()
+	path := append([]int(nil), prefix...)
+	last := prefix[len(prefix)-1]
+	st := []*StackEntry{{v: last, moves: s.moves(last)}}
+
+	for len(st) > 0 {
+		// Timelimit check
+		select {
+		case <-ctx.Done():
+			return model.ErrorTimelimitReached
+		default:
+		}
+
+		// Pop stack when all moves are tried or the path can't be improved any more
+		cur := st[len(st)-1]
+		if cur.i == len(cur.moves) || best.length() == best.limit {
+			s.visited[cur.v] = false
+			st = st[:len(st)-1]
+			path = path[:len(path)-1]
+			continue
+		}
+
+		next := cur.moves[cur.i]
+		cur.i++
+		progress.visit()
+
+		// Check exit conditions
+		if g.exit[next] {
+			best.offer(path, next)
+			continue
+		}
+
+		s.visited[next] = true
+		b := s.bound(next)
+		if b == 0 || len(path)+1+b <= best.length() {
+			s.visited[next] = false
+			continue
+		}
+
+		path = append(path, next)
+		st = append(st, &StackEntry{v: next, moves: s.moves(next)})
+	}
+
+	return nil
+}
+
+// longestPath is the longest path found so far, shared by searches running in parallel.
+type longestPath struct {
+	mu    sync.Mutex
+	path  []int
+	n     int64 // Length of the path, read without the lock for pruning
+	limit int   // No path can be longer
+}
+
+func (p *longestPath) length() int {
+	return int(atomic.LoadInt64(&p.n))
+}
+
+// offer keeps the path followed by the exit next if it is longer than the current one.
+func (p *longestPath) offer(path []int, next int) {
+	if len(path)+1 <= p.length() {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(path)+1 > len(p.path) {
+		p.path = append(append(p.path[:0:0], path...), next)
+		atomic.StoreInt64(&p.n, int64(len(p.path)))
+	}
+}
+
+// searchParallel splits the search into subtrees at its top levels and searches them with several goroutines.
+// Subtrees are taken in the order the sequential search goes through them, so long paths are still found early,
+// and every goroutine prunes its branches against the longest path found by any of them.
+func searchParallel(ctx context.Context, g *cellGraph, workers int) ([]int, error) {
+	s := newLongestPathSearch(g)
+	best := s.start(ctx)
+	if best == nil {
+		return nil, nil
+	}
+
+	prefixes, err := s.split(ctx, best, workers*subtreesPerWorker)
+	if err != nil {
+		return best.path, err
+	}
+	tasks := make(chan []int, len(prefixes))
+	for _, prefix := range prefixes {
+		tasks <- prefix
+	}
+	close(tasks)
+
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			s := newLongestPathSearch(g)
+			for prefix := range tasks {
+				if err := s.search(ctx, prefix, best); err != nil {
+					errs <- err
+					return
+				}
+			}
+			errs <- nil
+		}()
+	}
+	for i := 0; i < workers; i++ {
+		if e := <-errs; e != nil {
+			err = e
+		}
+	}
+
+	return best.path, err
+}
+
+// split expands the search tree level by level until there are at least n subtrees, or up to maxSplitDepth levels
+// for mazes with long corridors. Paths reaching an exit on the way are offered as results.
+func (s *longestPathSearch) split(ctx context.Context, best *longestPath, n int) ([][]int, error) {
+	g := s.g
+	progress := progressFromContext(ctx)
+	level := [][]int{{0}}
+
+	for depth := 0; len(level) > 0 && len(level) < n && depth < maxSplitDepth; depth++ {
+		// Timelimit check
+		select {
+		case <-ctx.Done():
+			return nil, model.ErrorTimelimitReached
+		default:
+		}
+
+		var next [][]int
+		for _, path := range level {
+			for _, v := range path {
+				s.visited[v] = true
+			}
+			for _, w := range s.moves(path[len(path)-1]) {
+				progress.visit()
+				if g.exit[w] {
+					best.offer(path, w)
+					continue
+				}
+				s.visited[w] = true
+				if s.bound(w) > 0 {
+					next = append(next, append(append([]int(nil), path...), w))
+				}
+				s.visited[w] = false
+			}
+			for _, v := range path {
+				s.visited[v] = false
+			}
+		}
+		level = next
+	}
+
+	return level, nil
+}
+
+// moves lists the moves from v, those leaving fewer onward moves go first to find long paths early. Exits go last.
+func (s *longestPathSearch) moves(v int) []int {
+	g := s.g
+	var res, degree []int
+	for _, w := range g.adj[v] {
+		if s.visited[w] {
+			continue
+		}
+		d := len(g.adj) // Greater than any number of moves
+		if !g.exit[w] {
+			d = 0
+			for _, u := range g.adj[w] {
+				if !s.visited[u] {
+					d++
+				}
+			}
+		}
+		res = append(res, w)
+		degree = append(degree, d)
+	}
+
+	sort.Stable(byDegree{res, degree})
+	return res
+}
+
+type byDegree struct{ cells, degree []int }
+
+func (d byDegree) Len() int           { return len(d.cells) }
+func (d byDegree) Less(i, j int) bool { return d.degree[i] < d.degree[j] }
+func (d byDegree) Swap(i, j int) {
+	d.cells[i], d.cells[j] = d.cells[j], d.cells[i]
+	d.degree[i], d.degree[j] = d.degree[j], d.degree[i]
+}
+
+// bound returns an upper bound for the number of cells a path from the current cell cur can still visit,
+// the exit included, or 0 if no exit can be reached. Cells of the path are marked as visited.
+func (s *longestPathSearch) bound(cur int) int {
+	g := s.g
+	for _, v := range s.touched {
+		s.disc[v] = 0
+	}
+	s.touched = append(s.touched[:0], cur)
+	s.stack = s.stack[:0]
+	s.blocks = s.blocks[:0]
+	s.blockCells = s.blockCells[:0]
+
+	// Non-recursive Tarjan's algorithm, blocks are found in post-order
+	s.time = 1
+	s.disc[cur], s.low[cur] = s.time, s.time
+	s.frames = append(s.frames[:0], tarjanFrame{v: cur, parent: -1})
+	for len(s.frames) > 0 {
+		f := &s.frames[len(s.frames)-1]
+		if f.i < len(g.adj[f.v]) {
+			w := g.adj[f.v][f.i]
+			f.i++
+			if g.exit[w] || (s.visited[w] && w != cur) {
+				continue
+			}
+			if s.disc[w] == 0 {
+				s.time++
+				s.disc[w], s.low[w] = s.time, s.time
+				s.touched = append(s.touched, w)
+				s.stack = append(s.stack, w)
+				s.frames = append(s.frames, tarjanFrame{v: w, parent: f.v})
+			} else if w != f.parent && s.disc[w] < s.low[f.v] {
+				s.low[f.v] = s.disc[w]
+			}
+			continue
+		}
+
+		v := f.v
+		s.frames = s.frames[:len(s.frames)-1]
+		if len(s.frames) == 0 {
+			break
+		}
+		p := s.frames[len(s.frames)-1].v
+		if s.low[v] < s.low[p] {
+			s.low[p] = s.low[v]
+		}
+
+		// p separates v and its subtree from the rest, they make a block together
+		if s.low[v] >= s.disc[p] {
+			b := block{top: p, first: len(s.blockCells)}
+			for {
+				x := s.stack[len(s.stack)-1]
+				s.stack = s.stack[:len(s.stack)-1]
+				s.blockCells = append(s.blockCells, x)
+				if x == v {
+					break
+				}
+			}
+			b.last = len(s.blockCells)
+			s.blocks = append(s.blocks, b)
+		}
+	}
+
+	// Blocks closer to the current cell go first, their top cells are in parent blocks
+	res := 0
+	if g.nearExit[cur] {
+		res = 1
+	}
+	s.reach[cur] = 1
+	for i := len(s.blocks) - 1; i >= 0; i-- {
+		b := s.blocks[i]
+		cells := s.blockCells[b.first:b.last]
+		size := len(cells) + 1
+
+		// Cells of the colour of the top cell and of the other one
+		same, other := 1, 0
+		if g.bipartite {
+			for _, x := range cells {
+				if g.color[x] == g.color[b.top] {
+					same++
+				} else {
+					other++
+				}
+			}
+		}
+
+		for _, x := range cells {
+			// Cells of the path from the top cell to x
+			n := size
+			if g.bipartite {
+				if g.color[x] == g.color[b.top] {
+					n = minInt(n, 2*minInt(other, same-1)+1)
+				} else {
+					n = minInt(n, 2*minInt(same, other))
+				}
+			}
+			s.reach[x] = s.reach[b.top] + n - 1
+			if g.nearExit[x] && s.reach[x] > res {
+				res = s.reach[x]
+			}
+		}
+	}
+
+	return res
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/egurnov/maze-api/maze-api/model"
+)
+
+// Random small mazes are solved by the plain search, the dynamic programming and the branch and bound search,
+// sequential and parallel.
+func TestSolveMaxMatchesPlain(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	movementNames := []string{model.MovementOrthogonal, model.MovementDiagonal, model.MovementDiagonalCut, model.MovementKnight}
+
+	for i := 0; i < 300; i++ {
+		rows, cols := 3+rnd.Intn(4), 3+rnd.Intn(4)
+		maze := make([][]bool, rows)
+		for r := range maze {
+			maze[r] = make([]bool, cols)
+			for c := range maze[r] {
+				maze[r][c] = rnd.Intn(5) < 2
+			}
+		}
+		maze[0][0] = false
+		mv := movements[movementNames[rnd.Intn(len(movementNames))]]
+		isExit := func(c Coords) bool { return c.Row == rows-1 }
+		if rnd.Intn(2) == 0 {
+			exit := Coords{rnd.Intn(rows), cols - 1}
+			isExit = func(c Coords) bool { return c == exit }
+		}
+		desc := fmt.Sprintf("maze %d: %v", i, maze)
+
+		exp, _, expErr := searchPlain(context.Background(), maze, mv, Coords{0, 0}, isExit, 0)
+
+		g := newCellGraph(maze, mv, Coords{0, 0}, isExit)
+		search, err := newLongestPathSearch(g).run(context.Background())
+		NewWithT(t).Expect(err).ToNot(HaveOccurred(), desc)
+		parallel, err := searchParallel(context.Background(), g, 2+rnd.Intn(3))
+		NewWithT(t).Expect(err).ToNot(HaveOccurred(), desc)
+		paths := [][]int{search, parallel}
+		// The dynamic programming works up to 32 cells, but slows down the test
+		if len(g.cells) <= 20 {
+			dp, err := g.longestPathDP(context.Background())
+			NewWithT(t).Expect(err).ToNot(HaveOccurred(), desc)
+			paths = append(paths, dp)
+		}
+
+		for _, path := range paths {
+			if expErr != nil {
+				NewWithT(t).Expect(path).To(BeNil(), desc)
+				continue
+			}
+			NewWithT(t).Expect(path).To(HaveLen(len(exp)), desc)
+			expectValidPath(t, g, path, desc)
+		}
+	}
+}
+
+// countdownCtx is done after its Done method has been called limit times, it stops a search at a given point.
+// It is safe for concurrent use, so parallel searches can be stopped too.
+type countdownCtx struct {
+	context.Context
+	calls, limit int64
+}
+
+func (c *countdownCtx) Done() <-chan struct{} {
+	if atomic.AddInt64(&c.calls, 1) <= c.limit {
+		return nil
+	}
+	done := make(chan struct{})
+	close(done)
+	return done
+}
+
+func (c *countdownCtx) Err() error {
+	if atomic.LoadInt64(&c.calls) <= c.limit {
+		return nil
+	}
+	return context.DeadlineExceeded
+}
+
+// The plain search runs out of moves after finding a path, then the search is stopped before it finds any.
+// The path of the plain search is returned.
+func TestSolveMaxKeepsPlainPath(t *testing.T) {
+	g := NewWithT(t)
+	maze, mv, start, isExit, err := prepareMaze(&model.Maze{Rows: 12, Cols: 12, Entrance: "A1", Walls: []string{"J1", "G2", "H2", "L2", "A3", "G3", "H3", "J3", "L4", "E5", "J6", "H9", "I9", "J9", "K9", "A10", "B10", "C10", "F10", "I10", "E11"}})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	ctx := &countdownCtx{Context: context.Background(), limit: math.MaxInt64}
+	plain, done, err := searchPlain(ctx, maze, mv, start, isExit, plainMoves+plainMovesPerCell*len(maze)*len(maze[0]))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(done).To(BeFalse())
+	g.Expect(plain).ToNot(BeEmpty())
+
+	// One more check before the plain search starts
+	res, err := solveMax(&countdownCtx{Context: context.Background(), limit: ctx.calls + 1}, maze, mv, start, isExit, 1)
+	g.Expect(err).To(MatchError(model.ErrorTimelimitReached))
+	g.Expect(res).To(Equal(plain))
+}
+
+// A search stopped after the plain search found a path returns a path at least as long, not the shortest one.
+func TestSolveMaxBestEffortTimelimit(t *testing.T) {
+	m := &model.Maze{Rows: 12, Cols: 12, Entrance: "A1", Walls: []string{"J1", "G2", "H2", "L2", "A3", "G3", "H3", "J3", "L4", "E5", "J6", "H9", "I9", "J9", "K9", "A10", "B10", "C10", "F10", "I10", "E11"}}
+	maze, mv, start, isExit, err := prepareMaze(m)
+	NewWithT(t).Expect(err).ToNot(HaveOccurred())
+
+	ctx := &countdownCtx{Context: context.Background(), limit: math.MaxInt64}
+	plain, _, err := searchPlain(ctx, maze, mv, start, isExit, plainMoves+plainMovesPerCell*len(maze)*len(maze[0]))
+	NewWithT(t).Expect(err).ToNot(HaveOccurred())
+
+	for _, workers := range []int{1, 4} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			// The hard maze isn't solved with this many checks
+			res, optimal, err := SolveMaxBestEffort(&countdownCtx{Context: context.Background(), limit: 3 * ctx.calls}, m, workers)
+
+			g := NewWithT(t)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(optimal).To(BeFalse())
+			// Much longer than the shortest path of 15 cells
+			g.Expect(len(res)).To(BeNumerically(">=", len(plain)))
+			g.Expect(len(plain)).To(BeNumerically(">", 15))
+			g.Expect(res[0]).To(Equal("A1"))
+			g.Expect(res[len(res)-1]).To(HaveSuffix("12"))
+		})
+	}
+}
+
+// A search takes the cores not used by other searches, but at least one.
+func TestSolveCores(t *testing.T) {
+	g := NewWithT(t)
+	cores := &SolveCores{}
+
+	n, release := cores.take(runtime.NumCPU() + 1)
+	g.Expect(n).To(Equal(runtime.NumCPU()))
+	other, releaseOther := cores.take(4)
+	g.Expect(other).To(Equal(1))
+	releaseOther()
+	release()
+	g.Expect(cores.solving).To(BeZero())
+
+	n, release = cores.take(1)
+	g.Expect(n).To(Equal(1))
+	release()
+
+	n, release = (*SolveCores)(nil).take(4)
+	g.Expect(n).To(Equal(4))
+	release()
+}
+
+func expectValidPath(t *testing.T, g *cellGraph, path []int, desc string) {
+	gm := NewWithT(t)
+	gm.Expect(path[0]).To(Equal(0), desc)
+	gm.Expect(g.exit[path[len(path)-1]]).To(BeTrue(), desc)
+	seen := map[int]bool{}
+	for i, v := range path {
+		gm.Expect(seen[v]).To(BeFalse(), desc)
+		seen[v] = true
+		if i > 0 {
+			gm.Expect(g.adj[path[i-1]]).To(ContainElement(v), desc)
+		}
+	}
+}
+
+func BenchmarkSolveMax(b *testing.B) {
+	examples := []struct {
+		desc  string
+		maze  *model.Maze
+		plain bool // The plain search finishes in reasonable time
+	}{
+		{
+			desc:  "example",
+			maze:  &model.Maze{Rows: 8, Cols: 8, Entrance: "A1", Walls: []string{"C1", "G1", "A2", "C2", "E2", "G2", "C3", "E3", "B4", "C4", "E4", "F4", "G4", "B5", "E5", "B6", "D6", "E6", "G6", "H6", "B7", "D7", "G7", "B8"}},
+			plain: true,
+		},
+		{
+			desc:  "2 paths",
+			maze:  &model.Maze{Rows: 8, Cols: 8, Entrance: "A1", Walls: []string{"C1", "G1", "A2", "C2", "D2", "E2", "G2", "E3", "B4", "C4", "E4", "F4", "G4", "C6", "E5", "B6", "D6", "E6", "G6", "H6", "B7", "D7", "G7", "B8"}},
+			plain: true,
+		},
+		{
+			desc:  "exits",
+			maze:  &model.Maze{Rows: 4, Cols: 4, Entrance: "A1", Walls: []string{"B1", "B2"}, Exits: []string{"D1", "A4"}},
+			plain: true,
+		},
+		{
+			desc:  "open 6x6",
+			maze:  &model.Maze{Rows: 6, Cols: 6, Entrance: "A1", Walls: []string{"A6", "B6", "C6", "D6", "E6"}},
+			plain: true,
+		},
+		{
+			desc: "open 10x10",
+			maze: &model.Maze{Rows: 10, Cols: 10, Entrance: "A1", Walls: []string{"A10", "B10", "C10", "D10", "E10", "F10", "G10", "H10", "I10"}},
+		},
+		{
+			// Scattered walls, the search takes about 0.1s on one core
+			desc: "hard 11x11",
+			maze: &model.Maze{Rows: 11, Cols: 11, Entrance: "A1", Walls: []string{"K1", "B2", "D2", "B3", "F3", "I3", "E4", "F4", "B5", "K5", "B7", "C7", "H7", "C10", "H10", "J10", "F11"}},
+		},
+	}
+
+	for _, ex := range examples {
+		maze, mv, start, isExit, err := prepareMaze(ex.maze)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		if ex.plain {
+			b.Run(ex.desc+"/plain", func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, _, err := searchPlain(context.Background(), maze, mv, start, isExit, 0); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+		b.Run(ex.desc, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := solveMax(context.Background(), maze, mv, start, isExit, 1); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(ex.desc+"/parallel", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := solveMax(context.Background(), maze, mv, start, isExit, runtime.NumCPU()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/egurnov/maze-api/maze-api/model"
+)
+
+// SolveAllMin returns up to limit shortest paths and the number of all shortest paths.
+// Paths are ordered the same way for the same maze.
+func SolveAllMin(ctx context.Context, m *model.Maze, limit int) (*model.ShortestPaths, error) {
+	maze, mv, start, isExit, err := prepareMaze(m)
+	if err != nil {
+		return nil, err
+	}
+
+	layers, err := shortestLayers(ctx, maze, mv, start, isExit)
+	if err != nil {
+		return nil, err
+	}
+	if len(layers.exits) == 0 {
+		return nil, model.ErrorNoSolution
+	}
+
+	paths, err := layers.paths(ctx, maze, mv, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &model.ShortestPaths{
+		Paths: make([][]string, len(paths)),
+		Total: layers.total(),
+	}
+	for i, path := range paths {
+		res.Paths[i] = pathToA1(path)
+	}
+
+	return res, nil
+}
+
+// Stats counts the shortest and the simple paths of the maze. A maze without a solution has zero paths.
+func Stats(ctx context.Context, m *model.Maze) (*model.MazeStats, error) {
+	maze, mv, start, isExit, err := prepareMaze(m)
+	if err != nil {
+		return nil, err
+	}
+
+	layers, err := shortestLayers(ctx, maze, mv, start, isExit)
+	if err != nil {
+		return nil, err
+	}
+	if len(layers.exits) == 0 {
+		return &model.MazeStats{ShortestPaths: new(big.Int)}, nil
+	}
+
+	simple, err := countSimplePaths(ctx, maze, mv, start, isExit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.MazeStats{
+		ShortestPathLength: layers.dist[layers.exits[0].Row][layers.exits[0].Col] + 1,
+		ShortestPaths:      layers.total(),
+		SimplePaths:        simple,
+	}, nil
+}
+
+func prepareMaze(m *model.Maze) (maze [][]bool, mv *movement, start Coords, isExit func(Coords) bool, err error) {
+	maze, err = makeMaze(m.Rows, m.Cols, m.Walls)
+	if err != nil {
+		return
+	}
+	start, err = A1ToCoords(m.Entrance)
+	if err != nil {
+		return
+	}
+	isExit, err = makeExits(m.Rows, m.Exits)
+	if err != nil {
+		return
+	}
+	mv, err = getMovement(m.Movement)
+	return
+}
+
+// bfsLayers is the result of a Breadth First Search from the entrance that stops at the layer of the nearest exits.
+type bfsLayers struct {
+	dist  [][]int      // Steps from the entrance, -1 for cells that weren't reached
+	ways  [][]*big.Int // Number of shortest paths from the entrance to the cell
+	exits []Coords     // Nearest exits in the order they were found
+}
+
+// shortestLayers counts shortest paths to every cell while searching: a cell can be reached the same number of ways
+// as all its neighbours in the previous layer together. Those are complete when the cell is taken from the queue.
+// Paths don't go on from exits, shortest paths never pass through an exit anyway.
+func shortestLayers(ctx context.Context, maze [][]bool, mv *movement, start Coords, isExit func(Coords) bool) (*bfsLayers, error) {
+	rows := len(maze)
+	cols := len(maze[0])
+	progress := progressFromContext(ctx)
+
+	res := &bfsLayers{
+		dist: make([][]int, rows),
+		ways: make([][]*big.Int, rows),
+	}
+	for i := range res.dist {
+		res.dist[i] = make([]int, cols)
+		res.ways[i] = make([]*big.Int, cols)
+		for j := range res.dist[i] {
+			res.dist[i][j] = -1
+		}
+	}
+	res.dist[start.Row][start.Col] = 0
+	res.ways[start.Row][start.Col] = big.NewInt(1)
+
+	q := []Coords{start}
+	for i := 0; i < len(q); i++ {
+		// Timelimit check
+		select {
+		case <-ctx.Done():
+			return nil, model.ErrorTimelimitReached
+		default:
+		}
+
+		cur := q[i]
+		curDist := res.dist[cur.Row][cur.Col]
+
+		// The layer of the nearest exits is done
+		if len(res.exits) > 0 && curDist > res.dist[res.exits[0].Row][res.exits[0].Col] {
+			break
+		}
+		progress.visit()
+
+		if cur != start && isExit(cur) {
+			res.exits = append(res.exits, cur)
+			continue
+		}
+		// Cells of the next layer aren't needed
+		if len(res.exits) > 0 {
+			continue
+		}
+
+		for _, delta := range mv.deltas {
+			if !mv.canMove(maze, cur, delta) {
+				continue
+			}
+			next := Coords{cur.Row + delta.Row, cur.Col + delta.Col}
+			switch res.dist[next.Row][next.Col] {
+			case -1:
+				res.dist[next.Row][next.Col] = curDist + 1
+				res.ways[next.Row][next.Col] = new(big.Int).Set(res.ways[cur.Row][cur.Col])
+				q = append(q, next)
+			case curDist + 1:
+				res.ways[next.Row][next.Col].Add(res.ways[next.Row][next.Col], res.ways[cur.Row][cur.Col])
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func (l *bfsLayers) total() *big.Int {
+	res := new(big.Int)
+	for _, e := range l.exits {
+		res.Add(res, l.ways[e.Row][e.Col])
+	}
+	return res
+}
+
+// paths lists up to limit shortest paths. It is a non-recursive Depth First Search going back from the exits,
+// every step goes to a neighbour in the previous layer, so every branch ends at the entrance.
+func (l *bfsLayers) paths(ctx context.Context, maze [][]bool, mv *movement, limit int) ([][]Coords, error) {
+	type StackEntry struct {
+		Coords
+		deltaI int
+	}
+	var res [][]Coords
+
+	for _, exit := range l.exits {
+		st := []*StackEntry{{exit, 0}}
+		for len(st) > 0 && len(res) < limit {
+			// Timelimit check
+			select {
+			case <-ctx.Done():
+				return nil, model.ErrorTimelimitReached
+			default:
+			}
+
+			cur := st[len(st)-1]
+			curDist := l.dist[cur.Row][cur.Col]
+
+			// Reached the entrance, the stack is the path from the exit
+			if curDist == 0 {
+				path := make([]Coords, len(st))
+				for i := range st {
+					path[len(st)-1-i] = st[i].Coords
+				}
+				res = append(res, path)
+				st = st[:len(st)-1]
+				continue
+			}
+
+			// Movements are symmetric, the delta from the previous cell is reversed
+			pushed := false
+			for cur.deltaI < len(mv.deltas) && !pushed {
+				delta := mv.deltas[cur.deltaI]
+				cur.deltaI++
+				prev := Coords{cur.Row + delta.Row, cur.Col + delta.Col}
+				if mv.canMove(maze, cur.Coords, delta) && l.dist[prev.Row][prev.Col] == curDist-1 {
+					st = append(st, &StackEntry{Coords: prev})
+					pushed = true
+				}
+			}
+			if !pushed {
+				st = st[:len(st)-1]
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// countSimplePaths is a non-recursive Depth First Search going through all paths.
+// Cells that can't reach any exit are skipped. The number of paths grows exponentially with the maze size.
+func countSimplePaths(ctx context.Context, maze [][]bool, mv *movement, start Coords, isExit func(Coords) bool) (int64, error) {
+	type StackEntry struct {
+		Coords
+		deltaI int
+	}
+	st := []*StackEntry{{start, 0}}
+	rows := len(maze)
+	cols := len(maze[0])
+	reachable := distancesToExits(maze, mv, isExit)
+	progress := progressFromContext(ctx)
+	var res int64
+
+	been := make([][]bool, rows)
+	for i := range been {
+		been[i] = make([]bool, cols)
+	}
+	been[start.Row][start.Col] = true
+
+	deltas := mv.deltas
+
+dfs:
+	for len(st) > 0 {
+		// Timelimit check
+		select {
+		case <-ctx.Done():
+			return 0, model.ErrorTimelimitReached
+		default:
+		}
+
+		cur := st[len(st)-1]
+		if cur.deltaI == 0 {
+			progress.visit()
+
+			// Don't go anywhere from exit
+			if cur.Coords != start && isExit(cur.Coords) {
+				res++
+				cur.deltaI = len(deltas)
+			}
+		}
+
+		for cur.deltaI < len(deltas) {
+			delta := deltas[cur.deltaI]
+			cur.deltaI++
+
+			next := Coords{cur.Row + delta.Row, cur.Col + delta.Col}
+			if mv.canMove(maze, cur.Coords, delta) && !been[next.Row][next.Col] && reachable[next.Row][next.Col] >= 0 {
+				been[next.Row][next.Col] = true
+				st = append(st, &StackEntry{Coords: next})
+				continue dfs
+			}
+		}
+
+		been[cur.Row][cur.Col] = false
+		st = st[:len(st)-1]
+	}
+
+	return res, nil
+}
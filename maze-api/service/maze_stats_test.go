@@ -0,0 +1,110 @@
+package service_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/egurnov/maze-api/maze-api/model"
+	"github.com/egurnov/maze-api/maze-api/service"
+)
+
+func TestSolveAllMin(t *testing.T) {
+	// Open 3x3 grid, the shortest paths to the opposite corner make 2 steps right and 2 steps down in any order
+	maze := &model.Maze{Rows: 3, Cols: 3, Entrance: "A1", ExitMode: model.ExitModeExplicit, Exits: []string{"C3"}}
+
+	t.Run("all", func(t *testing.T) {
+		res, err := service.SolveAllMin(context.Background(), maze, 100)
+
+		g := NewWithT(t)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res.Total).To(Equal(big.NewInt(6)))
+		g.Expect(res.Paths).To(HaveLen(6))
+		seen := map[string]bool{}
+		for _, path := range res.Paths {
+			g.Expect(path).To(HaveLen(5))
+			g.Expect(path[0]).To(Equal("A1"))
+			g.Expect(path[4]).To(Equal("C3"))
+			seen[path[1]+path[2]+path[3]] = true
+		}
+		g.Expect(seen).To(HaveLen(6))
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		res, err := service.SolveAllMin(context.Background(), maze, 2)
+
+		g := NewWithT(t)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res.Total).To(Equal(big.NewInt(6)))
+		g.Expect(res.Paths).To(HaveLen(2))
+	})
+
+	t.Run("includes min", func(t *testing.T) {
+		m := &model.Maze{Rows: 4, Cols: 4, Entrance: "A1", Walls: []string{"A4", "B4", "C4"}}
+		path, err := service.Solve(context.Background(), m, "min")
+		res, errAll := service.SolveAllMin(context.Background(), m, 100)
+
+		g := NewWithT(t)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(errAll).ToNot(HaveOccurred())
+		g.Expect(res.Total).To(Equal(big.NewInt(10)))
+		g.Expect(res.Paths).To(HaveLen(10))
+		g.Expect(res.Paths).To(ContainElement(path))
+	})
+
+	t.Run("more than int64", func(t *testing.T) {
+		m := &model.Maze{Rows: 40, Cols: 40, Entrance: "A1", ExitMode: model.ExitModeExplicit, Exits: []string{"AN40"}}
+		res, err := service.SolveAllMin(context.Background(), m, 1)
+
+		g := NewWithT(t)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res.Total).To(Equal(new(big.Int).Binomial(78, 39)))
+		g.Expect(res.Paths).To(HaveLen(1))
+	})
+
+	t.Run("no solution", func(t *testing.T) {
+		m := &model.Maze{Rows: 3, Cols: 3, Entrance: "A1", Walls: []string{"A2", "B2", "C2"}}
+		_, err := service.SolveAllMin(context.Background(), m, 100)
+
+		g := NewWithT(t)
+		g.Expect(err).To(MatchError(model.ErrorNoSolution))
+	})
+}
+
+func TestStats(t *testing.T) {
+	t.Run("open grid", func(t *testing.T) {
+		res, err := service.Stats(context.Background(), &model.Maze{Rows: 3, Cols: 3, Entrance: "A1", ExitMode: model.ExitModeExplicit, Exits: []string{"C3"}})
+
+		g := NewWithT(t)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res).To(Equal(&model.MazeStats{ShortestPathLength: 5, ShortestPaths: big.NewInt(6), SimplePaths: 12}))
+	})
+
+	t.Run("paths end at the first exit", func(t *testing.T) {
+		// A3 can't be passed through on the way to C3
+		res, err := service.Stats(context.Background(), &model.Maze{Rows: 3, Cols: 3, Entrance: "A1", Walls: []string{"B2"}, ExitMode: model.ExitModeAny})
+
+		g := NewWithT(t)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res).To(Equal(&model.MazeStats{ShortestPathLength: 3, ShortestPaths: big.NewInt(1), SimplePaths: 2}))
+	})
+
+	t.Run("no solution", func(t *testing.T) {
+		res, err := service.Stats(context.Background(), &model.Maze{Rows: 3, Cols: 3, Entrance: "A1", Walls: []string{"A2", "B2", "C2"}})
+
+		g := NewWithT(t)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res).To(Equal(&model.MazeStats{ShortestPaths: new(big.Int)}))
+	})
+
+	t.Run("timelimit", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := service.Stats(ctx, &model.Maze{Rows: 8, Cols: 8, Entrance: "A1", ExitMode: model.ExitModeExplicit, Exits: []string{"H8"}})
+
+		g := NewWithT(t)
+		g.Expect(err).To(MatchError(model.ErrorTimelimitReached))
+	})
+}
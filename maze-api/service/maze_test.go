@@ -3,10 +3,11 @@ package service_test
 import (
 	"context"
 	"testing"
-	"time"
 
 	. "github.com/onsi/gomega"
 
+	"github.com/egurnov/maze-api/maze-api/memstore"
+	"github.com/egurnov/maze-api/maze-api/model"
 	"github.com/egurnov/maze-api/maze-api/service"
 )
 
@@ -77,7 +78,7 @@ func TestSolveMin(t *testing.T) {
 	t.Run("example", func(t *testing.T) {
 		ctx := context.Background()
 
-		res, err := service.Solve(ctx, 8, 8, "A1", []string{"C1", "G1", "A2", "C2", "E2", "G2", "C3", "E3", "B4", "C4", "E4", "F4", "G4", "B5", "E5", "B6", "D6", "E6", "G6", "H6", "B7", "D7", "G7", "B8"}, "min")
+		res, err := service.Solve(ctx, &model.Maze{Rows: 8, Cols: 8, Entrance: "A1", Walls: []string{"C1", "G1", "A2", "C2", "E2", "G2", "C3", "E3", "B4", "C4", "E4", "F4", "G4", "B5", "E5", "B6", "D6", "E6", "G6", "H6", "B7", "D7", "G7", "B8"}}, "min")
 
 		g := NewWithT(t)
 		g.Expect(err).ToNot(HaveOccurred())
@@ -87,7 +88,7 @@ func TestSolveMin(t *testing.T) {
 	t.Run("no solution", func(t *testing.T) {
 		ctx := context.Background()
 
-		res, err := service.Solve(ctx, 8, 8, "A1", []string{"C1", "G1", "A2", "C2", "E2", "G2", "C3", "E3", "B4", "C4", "E4", "F4", "G4", "B5", "E5", "B6", "D6", "E6", "G6", "H6", "B7", "D7", "G7", "B8", "A8"}, "min")
+		res, err := service.Solve(ctx, &model.Maze{Rows: 8, Cols: 8, Entrance: "A1", Walls: []string{"C1", "G1", "A2", "C2", "E2", "G2", "C3", "E3", "B4", "C4", "E4", "F4", "G4", "B5", "E5", "B6", "D6", "E6", "G6", "H6", "B7", "D7", "G7", "B8", "A8"}}, "min")
 
 		g := NewWithT(t)
 		g.Expect(err).To(MatchError("no solution"))
@@ -97,7 +98,7 @@ func TestSolveMin(t *testing.T) {
 	t.Run("2 paths", func(t *testing.T) {
 		ctx := context.Background()
 
-		res, err := service.Solve(ctx, 8, 8, "A1", []string{"C1", "G1", "A2", "C2", "D2", "E2", "G2", "E3", "B4", "C4", "E4", "F4", "G4", "C6", "E5", "B6", "D6", "E6", "G6", "H6", "B7", "D7", "G7", "B8"}, "min")
+		res, err := service.Solve(ctx, &model.Maze{Rows: 8, Cols: 8, Entrance: "A1", Walls: []string{"C1", "G1", "A2", "C2", "D2", "E2", "G2", "E3", "B4", "C4", "E4", "F4", "G4", "C6", "E5", "B6", "D6", "E6", "G6", "H6", "B7", "D7", "G7", "B8"}}, "min")
 
 		g := NewWithT(t)
 		g.Expect(err).ToNot(HaveOccurred())
@@ -109,7 +110,7 @@ func TestSolveMax(t *testing.T) {
 	t.Run("example", func(t *testing.T) {
 		ctx := context.Background()
 
-		res, err := service.Solve(ctx, 8, 8, "A1", []string{"C1", "G1", "A2", "C2", "E2", "G2", "C3", "E3", "B4", "C4", "E4", "F4", "G4", "B5", "E5", "B6", "D6", "E6", "G6", "H6", "B7", "D7", "G7", "B8"}, "max")
+		res, err := service.Solve(ctx, &model.Maze{Rows: 8, Cols: 8, Entrance: "A1", Walls: []string{"C1", "G1", "A2", "C2", "E2", "G2", "C3", "E3", "B4", "C4", "E4", "F4", "G4", "B5", "E5", "B6", "D6", "E6", "G6", "H6", "B7", "D7", "G7", "B8"}}, "max")
 
 		g := NewWithT(t)
 		g.Expect(err).ToNot(HaveOccurred())
@@ -119,7 +120,7 @@ func TestSolveMax(t *testing.T) {
 	t.Run("no solution", func(t *testing.T) {
 		ctx := context.Background()
 
-		res, err := service.Solve(ctx, 8, 8, "A1", []string{"C1", "G1", "A2", "C2", "E2", "G2", "C3", "E3", "B4", "C4", "E4", "F4", "G4", "B5", "E5", "B6", "D6", "E6", "G6", "H6", "B7", "D7", "G7", "B8", "A8"}, "max")
+		res, err := service.Solve(ctx, &model.Maze{Rows: 8, Cols: 8, Entrance: "A1", Walls: []string{"C1", "G1", "A2", "C2", "E2", "G2", "C3", "E3", "B4", "C4", "E4", "F4", "G4", "B5", "E5", "B6", "D6", "E6", "G6", "H6", "B7", "D7", "G7", "B8", "A8"}}, "max")
 
 		g := NewWithT(t)
 		g.Expect(err).To(MatchError("no solution"))
@@ -129,18 +130,29 @@ func TestSolveMax(t *testing.T) {
 	t.Run("2 paths", func(t *testing.T) {
 		ctx := context.Background()
 
-		res, err := service.Solve(ctx, 8, 8, "A1", []string{"C1", "G1", "A2", "C2", "D2", "E2", "G2", "E3", "B4", "C4", "E4", "F4", "G4", "C6", "E5", "B6", "D6", "E6", "G6", "H6", "B7", "D7", "G7", "B8"}, "max")
+		res, err := service.Solve(ctx, &model.Maze{Rows: 8, Cols: 8, Entrance: "A1", Walls: []string{"C1", "G1", "A2", "C2", "D2", "E2", "G2", "E3", "B4", "C4", "E4", "F4", "G4", "C6", "E5", "B6", "D6", "E6", "G6", "H6", "B7", "D7", "G7", "B8"}}, "max")
 
 		g := NewWithT(t)
 		g.Expect(err).ToNot(HaveOccurred())
 		g.Expect(res).To(Equal([]string{"A1", "B1", "B2", "B3", "C3", "D3", "D4", "D5", "C5", "B5", "A5", "A6", "A7", "A8"}))
 	})
 
+	t.Run("open", func(t *testing.T) {
+		ctx := context.Background()
+
+		res, err := service.Solve(ctx, &model.Maze{Rows: 10, Cols: 10, Entrance: "A1", Walls: []string{"A10", "B10", "C10", "D10", "E10", "F10", "G10", "H10", "I10"}}, "max")
+
+		g := NewWithT(t)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res).To(HaveLen(91))
+		g.Expect(res[len(res)-1]).To(Equal("J10"))
+	})
+
 	t.Run("timelimit", func(t *testing.T) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
-		defer cancel()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
 
-		res, err := service.Solve(ctx, 10, 10, "A1", []string{"A10", "B10", "C10", "D10", "E10", "F10", "G10", "H10", "I10"}, "max")
+		res, err := service.Solve(ctx, &model.Maze{Rows: 12, Cols: 12, Entrance: "A1", Walls: []string{"J1", "G2", "H2", "L2", "A3", "G3", "H3", "J3", "L4", "E5", "J6", "H9", "I9", "J9", "K9", "A10", "B10", "C10", "F10", "I10", "E11"}}, "max")
 
 		g := NewWithT(t)
 		g.Expect(err).To(MatchError("time limit reached"))
@@ -148,11 +160,230 @@ func TestSolveMax(t *testing.T) {
 	})
 }
 
+func TestSolveMaxBestEffort(t *testing.T) {
+	t.Run("optimal", func(t *testing.T) {
+		maze := &model.Maze{Rows: 8, Cols: 8, Entrance: "A1", Walls: []string{"C1", "G1", "A2", "C2", "D2", "E2", "G2", "E3", "B4", "C4", "E4", "F4", "G4", "C6", "E5", "B6", "D6", "E6", "G6", "H6", "B7", "D7", "G7", "B8"}}
+		res, optimal, err := service.SolveMaxBestEffort(context.Background(), maze, 1)
+
+		g := NewWithT(t)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(optimal).To(BeTrue())
+		g.Expect(res).To(Equal([]string{"A1", "B1", "B2", "B3", "C3", "D3", "D4", "D5", "C5", "B5", "A5", "A6", "A7", "A8"}))
+	})
+
+	t.Run("timelimit", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// Nothing is searched, the shortest path is returned
+		maze := &model.Maze{Rows: 12, Cols: 12, Entrance: "A1", Walls: []string{"J1", "G2", "H2", "L2", "A3", "G3", "H3", "J3", "L4", "E5", "J6", "H9", "I9", "J9", "K9", "A10", "B10", "C10", "F10", "I10", "E11"}}
+		res, optimal, err := service.SolveMaxBestEffort(ctx, maze, 1)
+
+		g := NewWithT(t)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(optimal).To(BeFalse())
+		g.Expect(res).To(HaveLen(15))
+		g.Expect(res[0]).To(Equal("A1"))
+		g.Expect(res[len(res)-1]).To(HaveSuffix("12"))
+	})
+
+	t.Run("no solution", func(t *testing.T) {
+		maze := &model.Maze{Rows: 8, Cols: 8, Entrance: "A1", Walls: []string{"C1", "G1", "A2", "C2", "E2", "G2", "C3", "E3", "B4", "C4", "E4", "F4", "G4", "B5", "E5", "B6", "D6", "E6", "G6", "H6", "B7", "D7", "G7", "B8", "A8"}}
+		_, _, err := service.SolveMaxBestEffort(context.Background(), maze, 1)
+
+		g := NewWithT(t)
+		g.Expect(err).To(MatchError(model.ErrorNoSolution))
+	})
+}
+
+func TestSolveWith(t *testing.T) {
+	maze := &model.Maze{Rows: 8, Cols: 8, Entrance: "A1", Walls: []string{"C1", "G1", "A2", "C2", "E2", "G2", "C3", "E3", "B4", "C4", "E4", "F4", "G4", "B5", "E5", "B6", "D6", "E6", "G6", "H6", "B7", "D7", "G7", "B8"}}
+	longest, err := service.Solve(context.Background(), maze, "max")
+	NewWithT(t).Expect(err).ToNot(HaveOccurred())
+
+	for _, opts := range []*model.SolveOptions{
+		{Steps: "max"},
+		{Steps: "max", Workers: 4},
+		{Steps: "max", Workers: 4, BestEffort: true},
+	} {
+		path, optimal, err := service.SolveWith(context.Background(), maze, opts)
+
+		g := NewWithT(t)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(optimal).To(BeTrue())
+		g.Expect(path).To(HaveLen(len(longest)))
+	}
+
+	t.Run("movement", func(t *testing.T) {
+		path, _, err := service.SolveWith(context.Background(), maze, &model.SolveOptions{Steps: "min", Movement: model.MovementDiagonalCut})
+
+		g := NewWithT(t)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(len(path)).To(BeNumerically("<", 10))
+		g.Expect(maze.Movement).To(BeEmpty())
+	})
+}
+
+func TestSolveCheapest(t *testing.T) {
+	// Two corridors of the same length, the left one is muddy
+	maze := &model.Maze{Rows: 5, Cols: 3, Entrance: "A1", Walls: []string{"B2", "B3", "A5", "B5"}, Costs: map[string]int{"A3": 10}}
+
+	t.Run("example", func(t *testing.T) {
+		res, err := service.Solve(context.Background(), maze, "cheapest")
+
+		g := NewWithT(t)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res).To(Equal([]string{"A1", "B1", "C1", "C2", "C3", "C4", "C5"}))
+		g.Expect(service.PathCost(maze, res)).To(Equal(6))
+	})
+
+	t.Run("the other corridor", func(t *testing.T) {
+		maze := &model.Maze{Rows: 5, Cols: 3, Entrance: "A1", Walls: []string{"B2", "B3", "A5", "B5"}, Costs: map[string]int{"A2": 3, "C2": 5, "C3": 5}}
+
+		res, err := service.Solve(context.Background(), maze, "cheapest")
+
+		g := NewWithT(t)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res).To(Equal([]string{"A1", "A2", "A3", "A4", "B4", "C4", "C5"}))
+		g.Expect(service.PathCost(maze, res)).To(Equal(8))
+	})
+
+	t.Run("no costs", func(t *testing.T) {
+		walls := []string{"C1", "G1", "A2", "C2", "E2", "G2", "C3", "E3", "B4", "C4", "E4", "F4", "G4", "B5", "E5", "B6", "D6", "E6", "G6", "H6", "B7", "D7", "G7", "B8"}
+		res, err := service.Solve(context.Background(), &model.Maze{Rows: 8, Cols: 8, Entrance: "A1", Walls: walls}, "cheapest")
+
+		g := NewWithT(t)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res).To(HaveLen(10))
+	})
+
+	t.Run("no solution", func(t *testing.T) {
+		res, err := service.Solve(context.Background(), &model.Maze{Rows: 3, Cols: 3, Entrance: "A1", Walls: []string{"A3", "B3", "C3"}}, "cheapest")
+
+		g := NewWithT(t)
+		g.Expect(err).To(MatchError("no solution"))
+		g.Expect(res).To(BeNil())
+	})
+
+	t.Run("timelimit", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := service.Solve(ctx, maze, "cheapest")
+		NewWithT(t).Expect(err).To(MatchError("time limit reached"))
+	})
+}
+
+func TestValidateCosts(t *testing.T) {
+	walls := []string{"A4", "B4", "C4"}
+	testCases := []struct {
+		desc   string
+		costs  map[string]int
+		expErr string
+	}{
+		{"no costs", nil, ""},
+		{"valid", map[string]int{"A1": 1, "B2": 5, "D4": service.MaxCellCost}, ""},
+		{"invalid cell", map[string]int{"2B": 5}, "invalid cost cell: 2B"},
+		{"out of bounds", map[string]int{"E1": 5}, "invalid cost cell: E1"},
+		{"wall", map[string]int{"B4": 5}, "wall cannot have a cost: B4"},
+		{"zero", map[string]int{"B2": 0}, "invalid cost of B2: 0"},
+		{"too high", map[string]int{"B2": service.MaxCellCost + 1}, "invalid cost of B2: 1001"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := service.ValidateCosts(4, 4, walls, tc.costs)
+			g := NewWithT(t)
+			if len(tc.expErr) == 0 {
+				g.Expect(err).ToNot(HaveOccurred())
+			} else {
+				g.Expect(err).To(MatchError(tc.expErr))
+			}
+		})
+	}
+}
+
+func TestSolveExits(t *testing.T) {
+	maze := &model.Maze{Rows: 4, Cols: 4, Entrance: "A1", Walls: []string{"B1", "B2"}, ExitMode: model.ExitModeExplicit, Exits: []string{"D1", "A4"}}
+
+	t.Run("min", func(t *testing.T) {
+		res, err := service.Solve(context.Background(), maze, "min")
+
+		g := NewWithT(t)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res).To(Equal([]string{"A1", "A2", "A3", "A4"}))
+	})
+
+	t.Run("max", func(t *testing.T) {
+		res, err := service.Solve(context.Background(), maze, "max")
+
+		g := NewWithT(t)
+		g.Expect(err).ToNot(HaveOccurred())
+		// There are several longest paths, most of them reach D1, one reaches A4. The search tries D1 first.
+		g.Expect(res).To(HaveLen(12))
+		g.Expect(res[len(res)-1]).To(Equal("D1"))
+	})
+
+	t.Run("any exit", func(t *testing.T) {
+		res, err := service.Solve(context.Background(), &model.Maze{Rows: 4, Cols: 4, Entrance: "A1", Walls: []string{"A4", "B4"}, ExitMode: model.ExitModeAny}, "min")
+
+		g := NewWithT(t)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res).To(Equal([]string{"A1", "A2", "A3", "B3", "C3", "C4"}))
+	})
+}
+
+func TestSolveMovement(t *testing.T) {
+	// The entrance is closed orthogonally, the only exit is B3
+	maze := func(movement string) *model.Maze {
+		return &model.Maze{Rows: 3, Cols: 3, Entrance: "A1", Walls: []string{"B1", "A2", "A3", "C3"}, Movement: movement}
+	}
+
+	t.Run("orthogonal", func(t *testing.T) {
+		_, err := service.Solve(context.Background(), maze(model.MovementOrthogonal), "min")
+
+		g := NewWithT(t)
+		g.Expect(err).To(MatchError(model.ErrorNoSolution))
+	})
+
+	t.Run("diagonal", func(t *testing.T) {
+		_, err := service.Solve(context.Background(), maze(model.MovementDiagonal), "min")
+
+		g := NewWithT(t)
+		g.Expect(err).To(MatchError(model.ErrorNoSolution))
+	})
+
+	t.Run("diagonal with corner cuts", func(t *testing.T) {
+		res, err := service.Solve(context.Background(), maze(model.MovementDiagonalCut), "min")
+
+		g := NewWithT(t)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res).To(Equal([]string{"A1", "B2", "B3"}))
+	})
+
+	t.Run("knight", func(t *testing.T) {
+		res, err := service.Solve(context.Background(), maze(model.MovementKnight), "min")
+
+		g := NewWithT(t)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res).To(Equal([]string{"A1", "B3"}))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := service.Solve(context.Background(), maze("queen"), "min")
+
+		g := NewWithT(t)
+		g.Expect(err).To(MatchError("invalid movement: queen"))
+	})
+}
+
 func TestValidateMaze(t *testing.T) {
 	type maze struct {
 		GridSize string
 		Entrance string
 		Walls    []string
+		ExitMode string
+		Exits    []string
+		Movement string
 	}
 	testCases := []struct {
 		desc   string
@@ -267,10 +498,167 @@ func TestValidateMaze(t *testing.T) {
 			},
 			expErr: "invalid exit",
 		},
+		{
+			desc: "any exit",
+			maze: maze{
+				GridSize: "4x4",
+				Entrance: "A1",
+				Walls:    []string{"A4", "B4"},
+				ExitMode: model.ExitModeAny,
+			},
+			expErr: "",
+		},
+		{
+			desc: "any exit, none reachable",
+			maze: maze{
+				GridSize: "4x4",
+				Entrance: "A1",
+				Walls:    []string{"A3", "B3", "C3", "D3"},
+				ExitMode: model.ExitModeAny,
+			},
+			expErr: "invalid exit point",
+		},
+		{
+			desc: "explicit exits",
+			maze: maze{
+				GridSize: "4x4",
+				Entrance: "A1",
+				Walls:    []string{"A4", "B4"},
+				Exits:    []string{"D1", "C4"},
+			},
+			expErr: "",
+		},
+		{
+			desc: "explicit exits, one reachable",
+			maze: maze{
+				GridSize: "4x4",
+				Entrance: "A1",
+				Walls:    []string{"C1", "C2", "D2"},
+				ExitMode: model.ExitModeExplicit,
+				Exits:    []string{"D1", "A4"},
+			},
+			expErr: "",
+		},
+		{
+			desc: "explicit exits, none reachable",
+			maze: maze{
+				GridSize: "4x4",
+				Entrance: "A1",
+				Walls:    []string{"C1", "C2", "D2"},
+				Exits:    []string{"D1"},
+			},
+			expErr: "invalid exit point",
+		},
+		{
+			desc: "explicit mode without exits",
+			maze: maze{
+				GridSize: "4x4",
+				Entrance: "A1",
+				ExitMode: model.ExitModeExplicit,
+			},
+			expErr: "explicit exit mode requires exits",
+		},
+		{
+			desc: "exits in another mode",
+			maze: maze{
+				GridSize: "4x4",
+				Entrance: "A1",
+				Walls:    []string{"A4", "B4", "C4"},
+				ExitMode: model.ExitModeSingle,
+				Exits:    []string{"D4"},
+			},
+			expErr: "exits can only be set in explicit exit mode",
+		},
+		{
+			desc: "invalid exit mode",
+			maze: maze{
+				GridSize: "4x4",
+				Entrance: "A1",
+				Walls:    []string{"A4", "B4", "C4"},
+				ExitMode: "no-exit",
+			},
+			expErr: "invalid exit mode",
+		},
+		{
+			desc: "exit out of bounds",
+			maze: maze{
+				GridSize: "4x4",
+				Entrance: "A1",
+				Exits:    []string{"E4"},
+			},
+			expErr: "invalid exit: E4",
+		},
+		{
+			desc: "exit not on the border",
+			maze: maze{
+				GridSize: "4x4",
+				Entrance: "A1",
+				Exits:    []string{"B2"},
+			},
+			expErr: "exit must be on the border: B2",
+		},
+		{
+			desc: "exit is a wall",
+			maze: maze{
+				GridSize: "4x4",
+				Entrance: "A1",
+				Walls:    []string{"D4"},
+				Exits:    []string{"D4"},
+			},
+			expErr: "exit cannot be a wall: D4",
+		},
+		{
+			desc: "exit is the entrance",
+			maze: maze{
+				GridSize: "4x4",
+				Entrance: "A1",
+				Exits:    []string{"A1"},
+			},
+			expErr: "entrance cannot be an exit",
+		},
+		{
+			desc: "duplicate exit",
+			maze: maze{
+				GridSize: "4x4",
+				Entrance: "A1",
+				Exits:    []string{"D4", "D4"},
+			},
+			expErr: "duplicate exit: D4",
+		},
+		{
+			desc: "exit reachable diagonally",
+			maze: maze{
+				GridSize: "3x3",
+				Entrance: "A1",
+				Walls:    []string{"B1", "A2", "A3", "C3"},
+				Movement: model.MovementDiagonalCut,
+			},
+			expErr: "",
+		},
+		{
+			desc: "no corner cuts",
+			maze: maze{
+				GridSize: "3x3",
+				Entrance: "A1",
+				Walls:    []string{"B1", "A2", "A3", "C3"},
+				Movement: model.MovementDiagonal,
+			},
+			expErr: "invalid exit point",
+		},
+		{
+			desc: "invalid movement",
+			maze: maze{
+				GridSize: "4x4",
+				Entrance: "A1",
+				Walls:    []string{"A4", "B4", "C4"},
+				Movement: "queen",
+			},
+			expErr: "invalid movement: queen",
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
-			_, _, _, _, err := service.ValidateMaze(tc.maze.GridSize, tc.maze.Entrance, tc.maze.Walls)
+			_, _, _, _, err := service.ValidateMaze(tc.maze.GridSize, tc.maze.Entrance, tc.maze.Walls, tc.maze.ExitMode, tc.maze.Exits, tc.maze.Movement)
 			g := NewWithT(t)
 			if len(tc.expErr) == 0 {
 				g.Expect(err).ToNot(HaveOccurred())
@@ -280,3 +668,27 @@ func TestValidateMaze(t *testing.T) {
 		})
 	}
 }
+
+// Walls are stored in row-major order and canonical spelling, whatever the store
+func TestMazeServiceNormalizesWalls(t *testing.T) {
+	g := NewWithT(t)
+	s := &service.MazeService{Store: &memstore.MazeStore{Store: memstore.New()}}
+
+	id, err := s.Create(&model.Maze{Rows: 3, Cols: 3, Entrance: "A1", Walls: []string{"B2", "A03", "C01", "B2"}, UserID: 1})
+	g.Expect(err).ToNot(HaveOccurred())
+	maze, err := s.GetByID(id, 1)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(maze.Walls).To(Equal([]string{"C1", "B2", "A3"}))
+
+	maze.Walls = []string{"C03", "A2"}
+	g.Expect(s.Update(maze)).To(Succeed())
+	maze, err = s.GetByID(id, 1)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(maze.Walls).To(Equal([]string{"A2", "C3"}))
+
+	ids, err := s.CreateMany([]*model.Maze{{Rows: 2, Cols: 2, Entrance: "A1", Walls: []string{"B2", "A02"}, UserID: 1}})
+	g.Expect(err).ToNot(HaveOccurred())
+	maze, err = s.GetByID(ids[0], 1)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(maze.Walls).To(Equal([]string{"A2", "B2"}))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -17,42 +18,56 @@ type Coords struct {
 }
 
 const (
-	StepsMin = "min"
-	StepsMax = "max"
+	StepsMin      = "min"
+	StepsMax      = "max"
+	StepsCheapest = "cheapest"
 )
 
+const MaxCellCost = 1000
+
+func IsValidSteps(steps string) bool {
+	return steps == StepsMin || steps == StepsMax || steps == StepsCheapest
+}
+
 func A1ToCoords(s string) (Coords, error) {
-	row := 0
-	col := 0
-	i := 0
-	for ; i < len(s) && 'A' <= s[i] && s[i] <= 'Z'; i++ {
-		col = int(s[i]-'A'+1) + col*int('Z'-'A'+1)
-	}
-	if i == 0 {
-		return Coords{}, model.ErrInvalidInput
-	}
-	for ; i < len(s) && '0' <= s[i] && s[i] <= '9'; i++ {
-		row = int(s[i]-'0') + row*int('9'-'0'+1)
-	}
-	if i != len(s) {
-		return Coords{}, model.ErrInvalidInput
+	row, col, err := model.ParseA1(s)
+	if err != nil {
+		return Coords{}, err
 	}
-	return Coords{row - 1, col - 1}, nil
+	return Coords{row, col}, nil
 }
 
 func CoordsToA1(coords Coords) string {
-	res := ""
-	col := coords.Col + 1
-	for ; col > 0; col /= int('Z' - 'A' + 1) {
-		if col%int('Z'-'A'+1) == 0 {
-			res = "Z" + res
-			col -= 26
-		} else {
-			res = string(byte('A'+col%int('Z'-'A'+1)-1)) + res
+	return model.FormatA1(coords.Row, coords.Col)
+}
+
+// normalizeWalls spells the walls of the maze like CoordsToA1 and sorts them in row-major order without duplicates,
+// so that every store keeps and returns them the same way.
+func normalizeWalls(maze *model.Maze) error {
+	seen := make(map[Coords]bool, len(maze.Walls))
+	cells := make([]Coords, 0, len(maze.Walls))
+	for _, wall := range maze.Walls {
+		c, err := A1ToCoords(wall)
+		if err != nil {
+			return model.ErrInvalidInput
+		}
+		if !seen[c] {
+			seen[c] = true
+			cells = append(cells, c)
+		}
+	}
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].Row != cells[j].Row {
+			return cells[i].Row < cells[j].Row
 		}
+		return cells[i].Col < cells[j].Col
+	})
 
+	maze.Walls = make([]string, len(cells))
+	for i, c := range cells {
+		maze.Walls[i] = CoordsToA1(c)
 	}
-	return res + strconv.Itoa(coords.Row+1)
+	return nil
 }
 
 func areValid(c Coords, rows, cols int) bool {
@@ -96,6 +111,27 @@ func makeMaze(rows, cols int, walls []string) ([][]bool, error) {
 	return maze, nil
 }
 
+// makeCosts returns the cost of entering every cell, 1 unless listed.
+func makeCosts(rows, cols int, costs map[string]int) ([][]int, error) {
+	res := make([][]int, rows)
+	for i := range res {
+		res[i] = make([]int, cols)
+		for j := range res[i] {
+			res[i][j] = 1
+		}
+	}
+
+	for cell, cost := range costs {
+		c, err := A1ToCoords(cell)
+		if err != nil || !areValid(c, rows, cols) {
+			return nil, model.ErrInvalidInput
+		}
+		res[c.Row][c.Col] = cost
+	}
+
+	return res, nil
+}
+
 //nolint:unused
 func printMaze(maze [][]bool) {
 	fPrintMaze(maze, os.Stdout)
@@ -114,7 +150,8 @@ func fPrintMaze(maze [][]bool, f io.Writer) {
 	}
 }
 
-func ValidateMaze(gridSize, entrance string, walls []string) (rows, cols int, entranceCoords Coords, wallsCoords []Coords, err error) {
+// ValidateMaze checks the grid, the entrance, the walls and the exits. Explicit exits imply ExitModeExplicit if no mode is given.
+func ValidateMaze(gridSize, entrance string, walls []string, exitMode string, exits []string, movement string) (rows, cols int, entranceCoords Coords, wallsCoords []Coords, err error) {
 	// Grid size validation
 	rows, cols, err = parseGridSize(gridSize)
 	if err != nil {
@@ -141,28 +178,91 @@ func ValidateMaze(gridSize, entrance string, walls []string) (rows, cols int, en
 		wallsCoords = append(wallsCoords, coords)
 	}
 
-	// Exit point validation
 	maze, err := makeMaze(rows, cols, walls)
 	if err != nil {
 		return 0, 0, Coords{}, nil, err
 	}
 
-	// TODO: If there are multiple open cells in the last row, but only one of them is directly accessible, is this a valid maze?
-	count, err := countReachableExits(maze, entranceCoords)
+	// Exits validation
+	if exitMode == "" && len(exits) > 0 {
+		exitMode = model.ExitModeExplicit
+	}
+	switch exitMode {
+	case "", model.ExitModeSingle, model.ExitModeAny:
+		if len(exits) > 0 {
+			return 0, 0, Coords{}, nil, errors.New("exits can only be set in explicit exit mode")
+		}
+	case model.ExitModeExplicit:
+		if len(exits) == 0 {
+			return 0, 0, Coords{}, nil, errors.New("explicit exit mode requires exits")
+		}
+		seen := map[Coords]bool{}
+		for _, exit := range exits {
+			coords, err := A1ToCoords(exit)
+			if err != nil || !areValid(coords, rows, cols) {
+				return 0, 0, Coords{}, nil, errors.New("invalid exit: " + exit)
+			}
+			if coords.Row != 0 && coords.Row != rows-1 && coords.Col != 0 && coords.Col != cols-1 {
+				return 0, 0, Coords{}, nil, errors.New("exit must be on the border: " + exit)
+			}
+			if coords == entranceCoords {
+				return 0, 0, Coords{}, nil, errors.New("entrance cannot be an exit")
+			}
+			if maze[coords.Row][coords.Col] {
+				return 0, 0, Coords{}, nil, errors.New("exit cannot be a wall: " + exit)
+			}
+			if seen[coords] {
+				return 0, 0, Coords{}, nil, errors.New("duplicate exit: " + exit)
+			}
+			seen[coords] = true
+		}
+	default:
+		return 0, 0, Coords{}, nil, errors.New("invalid exit mode: " + exitMode)
+	}
+
+	// Exit point validation
+	isExit, err := makeExits(rows, exits)
+	if err != nil {
+		return 0, 0, Coords{}, nil, err
+	}
+
+	mv, err := getMovement(movement)
 	if err != nil {
 		return 0, 0, Coords{}, nil, err
 	}
 
-	if count != 1 {
+	count, err := countReachableExits(maze, mv, entranceCoords, isExit)
+	if err != nil {
+		return 0, 0, Coords{}, nil, err
+	}
+
+	if count == 0 || (count > 1 && (exitMode == "" || exitMode == model.ExitModeSingle)) {
 		return 0, 0, Coords{}, nil, errors.New("invalid exit point")
 	}
 
 	return rows, cols, entranceCoords, wallsCoords, nil
 }
 
+// makeExits returns a function telling if a cell is an exit. Without explicit exits every cell in the last row is one.
+func makeExits(rows int, exits []string) (func(Coords) bool, error) {
+	if len(exits) == 0 {
+		return func(c Coords) bool { return c.Row == rows-1 }, nil
+	}
+
+	set := make(map[Coords]bool, len(exits))
+	for _, exit := range exits {
+		c, err := A1ToCoords(exit)
+		if err != nil {
+			return nil, err
+		}
+		set[c] = true
+	}
+	return func(c Coords) bool { return set[c] }, nil
+}
+
 // countReachableExits uses a non-recursive Breadth First Search algorithm. Visited cells are added to a queue and processed in order.
 // Execution time is O(number of reachable cells), in the worst case O(rows*columns).
-func countReachableExits(maze [][]bool, start Coords) (int, error) {
+func countReachableExits(maze [][]bool, mv *movement, start Coords, isExit func(Coords) bool) (int, error) {
 	// Init
 	type QueueEntry = Coords
 	q := []QueueEntry{start}
@@ -182,21 +282,19 @@ func countReachableExits(maze [][]bool, start Coords) (int, error) {
 		cur := q[i]
 
 		// Check exit condition
-		if cur.Row == rows-1 {
+		if isExit(cur) {
 			exitCount++
 		}
 
 		// fmt.Printf(">>> Vising %v\n", cur) // DEBUG
 
 		// Iterate directions
-		for _, delta := range []Coords{{+1, 0}, {-1, 0}, {0, +1}, {0, -1}} {
+		for _, delta := range mv.deltas {
 
 			// fmt.Printf(">>> Trying %v\n", Coords{cur.Row + delta.Row, cur.Col + delta.Col}) // DEBUG
 
 			// If can go in this direction
-			if 0 <= cur.Row+delta.Row && cur.Row+delta.Row < rows &&
-				0 <= cur.Col+delta.Col && cur.Col+delta.Col < cols &&
-				!maze[cur.Row+delta.Row][cur.Col+delta.Col] &&
+			if mv.canMove(maze, cur, delta) &&
 				!been[cur.Row+delta.Row][cur.Col+delta.Col] {
 
 				// fmt.Printf(">>> Adding %v\n", Coords{cur.Row + delta.Row, cur.Col + delta.Col}) // DEBUG
@@ -212,3 +310,50 @@ func countReachableExits(maze [][]bool, start Coords) (int, error) {
 
 	return exitCount, nil
 }
+
+// ValidateCosts checks that costs are set on open cells and are between 1 and MaxCellCost.
+func ValidateCosts(rows, cols int, walls []string, costs map[string]int) error {
+	isWall := make(map[string]bool, len(walls))
+	for _, wall := range walls {
+		isWall[wall] = true
+	}
+
+	cells := make([]string, 0, len(costs))
+	for cell := range costs {
+		cells = append(cells, cell)
+	}
+	sort.Strings(cells) // Report the same error every time
+
+	for _, cell := range cells {
+		c, err := A1ToCoords(cell)
+		if err != nil || !areValid(c, rows, cols) {
+			return errors.New("invalid cost cell: " + cell)
+		}
+		if isWall[cell] {
+			return errors.New("wall cannot have a cost: " + cell)
+		}
+		if cost := costs[cell]; cost < 1 || cost > MaxCellCost {
+			return fmt.Errorf("invalid cost of %s: %d", cell, cost)
+		}
+	}
+
+	return nil
+}
+
+// PathCost sums the costs of the cells after the entrance.
+func PathCost(m *model.Maze, path []string) (int, error) {
+	costs, err := makeCosts(m.Rows, m.Cols, m.Costs)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for i := 1; i < len(path); i++ {
+		c, err := A1ToCoords(path[i])
+		if err != nil || !areValid(c, m.Rows, m.Cols) {
+			return 0, model.ErrInvalidInput
+		}
+		total += costs[c.Row][c.Col]
+	}
+	return total, nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/egurnov/maze-api/maze-api/model"
+)
+
+// VerifyPath checks every cell of the path and reports all problems found. A step from or to an invalid cell
+// isn't checked, the invalid cell is reported instead. The shortest path isn't compared, see MazeService.Verify.
+func VerifyPath(m *model.Maze, path []string) (*model.PathVerification, error) {
+	maze, mv, start, isExit, err := prepareMaze(m)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &model.PathVerification{Length: len(path)}
+	fail := func(i int, format string, args ...interface{}) {
+		cell := ""
+		if i < len(path) {
+			cell = path[i]
+		}
+		res.Errors = append(res.Errors, model.PathError{Index: i, Cell: cell, Message: fmt.Sprintf(format, args...)})
+	}
+	if len(path) == 0 {
+		fail(0, "the path is empty")
+		return res, nil
+	}
+
+	visited := make(map[Coords]int, len(path))
+	var prev *Coords
+	for i, a1 := range path {
+		cur, err := A1ToCoords(a1)
+		switch {
+		case err != nil:
+			fail(i, "invalid cell")
+			prev = nil
+			continue
+		case !areValid(cur, m.Rows, m.Cols):
+			fail(i, "out of bounds")
+			prev = nil
+			continue
+		case maze[cur.Row][cur.Col]:
+			fail(i, "wall")
+			prev = nil
+			continue
+		}
+
+		if i == 0 && cur != start {
+			fail(i, "the path must start at the entrance %s", CoordsToA1(start))
+		}
+		if first, ok := visited[cur]; ok {
+			fail(i, "visited twice, first at %d", first)
+		} else {
+			visited[cur] = i
+		}
+		if prev != nil && !(isMove(mv, *prev, cur) && mv.canMove(maze, *prev, Coords{cur.Row - prev.Row, cur.Col - prev.Col})) {
+			fail(i, "can't be reached from %s in one move", CoordsToA1(*prev))
+		}
+		if isExit(cur) && i < len(path)-1 {
+			fail(i, "the path must end at the first exit it reaches")
+		}
+		if !isExit(cur) && i == len(path)-1 {
+			fail(i, "the path must end at an exit")
+		}
+		prev = &cur
+	}
+
+	if len(res.Errors) > 0 {
+		return res, nil
+	}
+
+	res.Valid = true
+	res.Cost, err = PathCost(m, path)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// isMove tells if the movement has a move from one cell to the other, walls aside.
+func isMove(mv *movement, from, to Coords) bool {
+	for _, delta := range mv.deltas {
+		if from.Row+delta.Row == to.Row && from.Col+delta.Col == to.Col {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package service_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/egurnov/maze-api/maze-api/model"
+	"github.com/egurnov/maze-api/maze-api/service"
+)
+
+func TestVerifyPath(t *testing.T) {
+	// |_|X|_|_|
+	// |_|X|_|_|
+	// |_|_|_|_|
+	// |X|X|X|_|
+	walls := []string{"B1", "B2", "A4", "B4", "C4"}
+
+	testCases := []struct {
+		desc     string
+		movement string
+		path     []string
+		exp      *model.PathVerification
+	}{
+		{
+			desc: "shortest",
+			path: []string{"A1", "A2", "A3", "B3", "C3", "D3", "D4"},
+			exp:  &model.PathVerification{Valid: true, Length: 7, Cost: 6},
+		},
+		{
+			desc: "longer",
+			path: []string{"A1", "A2", "A3", "B3", "C3", "C2", "C1", "D1", "D2", "D3", "D4"},
+			exp:  &model.PathVerification{Valid: true, Length: 11, Cost: 10},
+		},
+		{
+			desc:     "movement",
+			movement: model.MovementDiagonalCut,
+			path:     []string{"A1", "A2", "B3", "C3", "D4"},
+			exp:      &model.PathVerification{Valid: true, Length: 5, Cost: 4},
+		},
+		{
+			desc:     "corner cut",
+			movement: model.MovementDiagonal,
+			path:     []string{"A1", "A2", "B3", "C3", "D4"},
+			exp: &model.PathVerification{Length: 5, Errors: []model.PathError{
+				{Index: 2, Cell: "B3", Message: "can't be reached from A2 in one move"},
+				{Index: 4, Cell: "D4", Message: "can't be reached from C3 in one move"},
+			}},
+		},
+		{
+			desc: "empty",
+			path: []string{},
+			exp: &model.PathVerification{Errors: []model.PathError{
+				{Index: 0, Message: "the path is empty"},
+			}},
+		},
+		{
+			desc: "invalid cells",
+			path: []string{"A1", "B1", "E2", "2A", "A3", "B3", "C3", "D3", "D4"},
+			exp: &model.PathVerification{Length: 9, Errors: []model.PathError{
+				{Index: 1, Cell: "B1", Message: "wall"},
+				{Index: 2, Cell: "E2", Message: "out of bounds"},
+				{Index: 3, Cell: "2A", Message: "invalid cell"},
+			}},
+		},
+		{
+			desc: "jump",
+			path: []string{"A1", "A3", "B3", "C3", "D3", "D4"},
+			exp: &model.PathVerification{Length: 6, Errors: []model.PathError{
+				{Index: 1, Cell: "A3", Message: "can't be reached from A1 in one move"},
+			}},
+		},
+		{
+			desc: "not from the entrance",
+			path: []string{"A2", "A3", "B3", "C3", "D3", "D4"},
+			exp: &model.PathVerification{Length: 6, Errors: []model.PathError{
+				{Index: 0, Cell: "A2", Message: "the path must start at the entrance A1"},
+			}},
+		},
+		{
+			desc: "loop",
+			path: []string{"A1", "A2", "A1", "A2", "A3", "B3", "C3", "D3", "D4"},
+			exp: &model.PathVerification{Length: 9, Errors: []model.PathError{
+				{Index: 2, Cell: "A1", Message: "visited twice, first at 0"},
+				{Index: 3, Cell: "A2", Message: "visited twice, first at 1"},
+			}},
+		},
+		{
+			desc: "not to an exit",
+			path: []string{"A1", "A2", "A3"},
+			exp: &model.PathVerification{Length: 3, Errors: []model.PathError{
+				{Index: 2, Cell: "A3", Message: "the path must end at an exit"},
+			}},
+		},
+		{
+			desc: "past the exit",
+			path: []string{"A1", "A2", "A3", "B3", "C3", "D3", "D4", "D3"},
+			exp: &model.PathVerification{Length: 8, Errors: []model.PathError{
+				{Index: 6, Cell: "D4", Message: "the path must end at the first exit it reaches"},
+				{Index: 7, Cell: "D3", Message: "visited twice, first at 5"},
+				{Index: 7, Cell: "D3", Message: "the path must end at an exit"},
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			maze := &model.Maze{Rows: 4, Cols: 4, Entrance: "A1", Walls: walls, Movement: tc.movement}
+			res, err := service.VerifyPath(maze, tc.path)
+
+			g := NewWithT(t)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(res).To(Equal(tc.exp))
+		})
+	}
+}
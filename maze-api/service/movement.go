@@ -0,0 +1,54 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/egurnov/maze-api/maze-api/model"
+)
+
+// movement lists the moves an agent can make from a cell. All movements are symmetric,
+// so the same moves lead back to the previous cell.
+type movement struct {
+	deltas       []Coords
+	noCornerCuts bool // Diagonal moves need both orthogonal neighbours to be open
+	bipartite    bool // Every move changes the colour of the cell on a chessboard
+}
+
+var (
+	orthogonalDeltas = []Coords{{+1, 0}, {-1, 0}, {0, +1}, {0, -1}}
+	diagonalDeltas   = []Coords{{+1, 0}, {-1, 0}, {0, +1}, {0, -1}, {+1, +1}, {+1, -1}, {-1, +1}, {-1, -1}}
+	knightDeltas     = []Coords{{+2, +1}, {+2, -1}, {-2, +1}, {-2, -1}, {+1, +2}, {+1, -2}, {-1, +2}, {-1, -2}}
+)
+
+var movements = map[string]*movement{
+	"":                        {deltas: orthogonalDeltas, bipartite: true},
+	model.MovementOrthogonal:  {deltas: orthogonalDeltas, bipartite: true},
+	model.MovementDiagonal:    {deltas: diagonalDeltas, noCornerCuts: true},
+	model.MovementDiagonalCut: {deltas: diagonalDeltas},
+	model.MovementKnight:      {deltas: knightDeltas, bipartite: true},
+}
+
+func IsValidMovement(m string) bool {
+	_, ok := movements[m]
+	return ok
+}
+
+func getMovement(m string) (*movement, error) {
+	mv, ok := movements[m]
+	if !ok {
+		return nil, errors.New("invalid movement: " + m)
+	}
+	return mv, nil
+}
+
+// canMove tells if the agent can go from the open cell by delta.
+func (mv *movement) canMove(maze [][]bool, from, delta Coords) bool {
+	to := Coords{from.Row + delta.Row, from.Col + delta.Col}
+	if !areValid(to, len(maze), len(maze[0])) || maze[to.Row][to.Col] {
+		return false
+	}
+	if mv.noCornerCuts && delta.Row != 0 && delta.Col != 0 {
+		return !maze[from.Row+delta.Row][from.Col] && !maze[from.Row][from.Col+delta.Col]
+	}
+	return true
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Progress counts cells visited by a solver. It is safe to read while the solver is running.
+type Progress struct {
+	explored int64
+}
+
+type progressKey struct{}
+
+// WithProgress makes solvers running with the returned context report to p.
+func WithProgress(ctx context.Context, p *Progress) context.Context {
+	return context.WithValue(ctx, progressKey{}, p)
+}
+
+func progressFromContext(ctx context.Context) *Progress {
+	p, _ := ctx.Value(progressKey{}).(*Progress)
+	return p
+}
+
+func (p *Progress) Explored() int64 {
+	if p == nil {
+		return 0
+	}
+	return atomic.LoadInt64(&p.explored)
+}
+
+func (p *Progress) visit() {
+	if p != nil {
+		atomic.AddInt64(&p.explored, 1)
+	}
+}
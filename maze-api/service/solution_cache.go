@@ -0,0 +1,102 @@
+package service
+
+import (
+	"container/list"
+	"sync"
+)
+
+// SolutionCache keeps the most recently used solutions in memory.
+// A nil cache is valid and caches nothing.
+type SolutionCache struct {
+	mu    sync.Mutex
+	size  int
+	lru   *list.List                         // front is the most recently used
+	items map[int64]map[string]*list.Element // maze ID -> steps -> element
+}
+
+type cachedSolution struct {
+	mazeID  int64
+	version int64 // of the maze
+	steps   string
+	path    []string
+}
+
+// NewSolutionCache returns a cache holding up to size solutions, nil if size is not positive.
+func NewSolutionCache(size int) *SolutionCache {
+	if size <= 0 {
+		return nil
+	}
+	return &SolutionCache{
+		size:  size,
+		lru:   list.New(),
+		items: map[int64]map[string]*list.Element{},
+	}
+}
+
+// Get only returns solutions found for the given version of the maze.
+func (c *SolutionCache) Get(mazeID, version int64, steps string) ([]string, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[mazeID][steps]
+	if !ok || e.Value.(*cachedSolution).version != version {
+		return nil, false
+	}
+	c.lru.MoveToFront(e)
+	return append([]string{}, e.Value.(*cachedSolution).path...), true
+}
+
+// Add keeps the solution of a newer version of the maze if there is one, a slow solver may finish
+// after the maze has been changed and solved again.
+func (c *SolutionCache) Add(mazeID, version int64, steps string, path []string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	path = append([]string{}, path...)
+	if e, ok := c.items[mazeID][steps]; ok {
+		s := e.Value.(*cachedSolution)
+		if s.version > version {
+			return
+		}
+		s.version, s.path = version, path
+		c.lru.MoveToFront(e)
+		return
+	}
+
+	if c.items[mazeID] == nil {
+		c.items[mazeID] = map[string]*list.Element{}
+	}
+	c.items[mazeID][steps] = c.lru.PushFront(&cachedSolution{mazeID: mazeID, version: version, steps: steps, path: path})
+
+	for c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+}
+
+// Invalidate removes all solutions of the maze.
+func (c *SolutionCache) Invalidate(mazeID int64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, e := range c.items[mazeID] {
+		c.remove(e)
+	}
+}
+
+// remove must be called with c.mu held.
+func (c *SolutionCache) remove(e *list.Element) {
+	s := c.lru.Remove(e).(*cachedSolution)
+	delete(c.items[s.mazeID], s.steps)
+	if len(c.items[s.mazeID]) == 0 {
+		delete(c.items, s.mazeID)
+	}
+}
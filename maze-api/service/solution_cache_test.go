@@ -0,0 +1,198 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/egurnov/maze-api/maze-api/memstore"
+	"github.com/egurnov/maze-api/maze-api/model"
+	"github.com/egurnov/maze-api/maze-api/service"
+)
+
+func TestSolutionCache(t *testing.T) {
+	t.Run("eviction", func(t *testing.T) {
+		g := NewWithT(t)
+		c := service.NewSolutionCache(2)
+
+		c.Add(1, 1, "min", []string{"A1"})
+		c.Add(1, 1, "max", []string{"A1", "A2"})
+		_, ok := c.Get(1, 1, "min") // 1/max is the least recently used now
+		g.Expect(ok).To(BeTrue())
+		c.Add(2, 1, "min", []string{"B1"})
+
+		_, ok = c.Get(1, 1, "max")
+		g.Expect(ok).To(BeFalse())
+		path, ok := c.Get(1, 1, "min")
+		g.Expect(ok).To(BeTrue())
+		g.Expect(path).To(Equal([]string{"A1"}))
+		path, ok = c.Get(2, 1, "min")
+		g.Expect(ok).To(BeTrue())
+		g.Expect(path).To(Equal([]string{"B1"}))
+	})
+
+	t.Run("invalidate", func(t *testing.T) {
+		g := NewWithT(t)
+		c := service.NewSolutionCache(10)
+
+		c.Add(1, 1, "min", []string{"A1"})
+		c.Add(1, 1, "max", []string{"A1", "A2"})
+		c.Add(2, 1, "min", []string{"B1"})
+		c.Invalidate(1)
+
+		_, ok := c.Get(1, 1, "min")
+		g.Expect(ok).To(BeFalse())
+		_, ok = c.Get(1, 1, "max")
+		g.Expect(ok).To(BeFalse())
+		path, ok := c.Get(2, 1, "min")
+		g.Expect(ok).To(BeTrue())
+		g.Expect(path).To(Equal([]string{"B1"}))
+	})
+
+	t.Run("versions", func(t *testing.T) {
+		g := NewWithT(t)
+		c := service.NewSolutionCache(10)
+
+		c.Add(1, 2, "min", []string{"A1"})
+		_, ok := c.Get(1, 1, "min")
+		g.Expect(ok).To(BeFalse())
+		_, ok = c.Get(1, 3, "min")
+		g.Expect(ok).To(BeFalse())
+
+		// A solver of an older version finishing late doesn't replace the newer solution
+		c.Add(1, 1, "min", []string{"A1", "A2"})
+		path, ok := c.Get(1, 2, "min")
+		g.Expect(ok).To(BeTrue())
+		g.Expect(path).To(Equal([]string{"A1"}))
+
+		c.Add(1, 3, "min", []string{"A1", "B1"})
+		path, ok = c.Get(1, 3, "min")
+		g.Expect(ok).To(BeTrue())
+		g.Expect(path).To(Equal([]string{"A1", "B1"}))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		g := NewWithT(t)
+		c := service.NewSolutionCache(0)
+
+		c.Add(1, 1, "min", []string{"A1"})
+		_, ok := c.Get(1, 1, "min")
+		g.Expect(ok).To(BeFalse())
+		c.Invalidate(1)
+	})
+}
+
+func TestMazeServiceSolveCached(t *testing.T) {
+	walls := []string{"C1", "G1", "A2", "C2", "E2", "G2", "C3", "E3", "B4", "C4", "E4", "F4", "G4", "B5", "E5", "B6", "D6", "E6", "G6", "H6", "B7", "D7", "G7", "B8"}
+	expSolution := &model.Solution{Path: []string{"A1", "B1", "B2", "B3", "A3", "A4", "A5", "A6", "A7", "A8"}, Cost: 9, Optimal: true}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	newService := func(t *testing.T, store *memstore.Store) (*service.MazeService, int64) {
+		s := &service.MazeService{
+			Store:     &memstore.MazeStore{Store: store},
+			Cache:     service.NewSolutionCache(10),
+			Solutions: &memstore.SolutionStore{Store: store},
+		}
+		id, err := s.Create(&model.Maze{Rows: 8, Cols: 8, Entrance: "A1", Walls: walls, UserID: 1})
+		NewWithT(t).Expect(err).ToNot(HaveOccurred())
+		return s, id
+	}
+
+	t.Run("cache", func(t *testing.T) {
+		g := NewWithT(t)
+		s, id := newService(t, memstore.New())
+
+		g.Expect(s.Solve(context.Background(), id, 1, &model.SolveOptions{Steps: "max"})).To(Equal(expSolution))
+		// A canceled context would fail if the maze was solved again
+		g.Expect(s.Solve(canceled, id, 1, &model.SolveOptions{Steps: "max"})).To(Equal(expSolution))
+
+		// Other users can't get the cached solution
+		_, err := s.Solve(canceled, id, 2, &model.SolveOptions{Steps: "max"})
+		g.Expect(err).To(MatchError(model.ErrNotFound))
+	})
+
+	t.Run("best effort", func(t *testing.T) {
+		g := NewWithT(t)
+		s, id := newService(t, memstore.New())
+
+		// The search stops right away, the shortest path is returned instead
+		res, err := s.Solve(canceled, id, 1, &model.SolveOptions{Steps: "max", BestEffort: true})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res.Path).To(Equal(expSolution.Path))
+		g.Expect(res.Optimal).To(BeFalse())
+
+		// Paths that aren't optimal aren't cached
+		_, err = s.Solve(canceled, id, 1, &model.SolveOptions{Steps: "max"})
+		g.Expect(err).To(MatchError(model.ErrorTimelimitReached))
+
+		g.Expect(s.Solve(context.Background(), id, 1, &model.SolveOptions{Steps: "max"})).To(Equal(expSolution))
+		g.Expect(s.Solve(canceled, id, 1, &model.SolveOptions{Steps: "max", BestEffort: true})).To(Equal(expSolution))
+	})
+
+	t.Run("solution store", func(t *testing.T) {
+		g := NewWithT(t)
+		store := memstore.New()
+		s, id := newService(t, store)
+
+		g.Expect(s.Solve(context.Background(), id, 1, &model.SolveOptions{Steps: "max"})).To(Equal(expSolution))
+
+		// Fresh cache, e.g. after a restart
+		s = &service.MazeService{Store: s.Store, Cache: service.NewSolutionCache(10), Solutions: s.Solutions}
+		g.Expect(s.Solve(canceled, id, 1, &model.SolveOptions{Steps: "max"})).To(Equal(expSolution))
+	})
+
+	t.Run("update while solving", func(t *testing.T) {
+		g := NewWithT(t)
+		store := memstore.New()
+		s, id := newService(t, store)
+
+		// The maze is changed right after the solver reads it
+		mazes := s.Store
+		s.Store = &updatedAfterRead{MazeStore: mazes, update: func(maze *model.Maze) {
+			maze.Walls = walls[1:]
+			g.Expect(mazes.Update(maze)).To(Succeed())
+		}}
+		g.Expect(s.Solve(context.Background(), id, 1, &model.SolveOptions{Steps: "max"})).To(Equal(expSolution))
+
+		// The solution of the old maze is neither cached nor saved
+		s.Store = mazes
+		_, err := s.Solve(canceled, id, 1, &model.SolveOptions{Steps: "max"})
+		g.Expect(err).To(MatchError(model.ErrorTimelimitReached))
+		s = &service.MazeService{Store: mazes, Cache: service.NewSolutionCache(10), Solutions: s.Solutions}
+		_, err = s.Solve(canceled, id, 1, &model.SolveOptions{Steps: "max"})
+		g.Expect(err).To(MatchError(model.ErrorTimelimitReached))
+	})
+
+	t.Run("update invalidates", func(t *testing.T) {
+		g := NewWithT(t)
+		s, id := newService(t, memstore.New())
+
+		g.Expect(s.Solve(context.Background(), id, 1, &model.SolveOptions{Steps: "max"})).To(Equal(expSolution))
+
+		maze, err := s.GetByID(id, 1)
+		g.Expect(err).ToNot(HaveOccurred())
+		maze.Walls = walls[1:]
+		g.Expect(s.Update(maze)).To(Succeed())
+
+		_, err = s.Solve(canceled, id, 1, &model.SolveOptions{Steps: "max"})
+		g.Expect(err).To(MatchError(model.ErrorTimelimitReached))
+	})
+}
+
+// updatedAfterRead calls update with a copy of the first maze it returns.
+type updatedAfterRead struct {
+	model.MazeStore
+	update func(*model.Maze)
+}
+
+func (s *updatedAfterRead) GetByID(id, userId int64) (*model.Maze, error) {
+	maze, err := s.MazeStore.GetByID(id, userId)
+	if err == nil && s.update != nil {
+		changed := *maze
+		s.update(&changed)
+		s.update = nil
+	}
+	return maze, err
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+)
+
+// SolveCores shares the cores between longest path searches running at the same time. The zero value is ready to use.
+type SolveCores struct {
+	solving int64 // Goroutines searching for longest paths at the moment
+}
+
+type solveCoresKey struct{}
+
+// WithSolveCores makes longest path searches running with the returned context take their goroutines from c.
+// The cores are taken only while a search runs, not for cached solutions or mazes solved without it.
+func WithSolveCores(ctx context.Context, c *SolveCores) context.Context {
+	return context.WithValue(ctx, solveCoresKey{}, c)
+}
+
+func solveCoresFromContext(ctx context.Context) *SolveCores {
+	c, _ := ctx.Value(solveCoresKey{}).(*SolveCores)
+	return c
+}
+
+// take returns the cores not used by other searches, up to workers, but at least one. release gives them back.
+// Without c all workers are returned.
+func (c *SolveCores) take(workers int) (n int, release func()) {
+	if c == nil {
+		return workers, func() {}
+	}
+
+	for {
+		solving := atomic.LoadInt64(&c.solving)
+		n = runtime.NumCPU() - int(solving)
+		if n > workers {
+			n = workers
+		}
+		if n < 1 {
+			n = 1
+		}
+		if atomic.CompareAndSwapInt64(&c.solving, solving, solving+int64(n)) {
+			return n, func() { atomic.AddInt64(&c.solving, -int64(n)) }
+		}
+	}
+}
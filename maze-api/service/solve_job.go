@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/egurnov/maze-api/maze-api/model"
+)
+
+// SolveJobService runs solvers in the background with a fixed number of workers.
+// Jobs are kept in memory and forgotten after the retention period once they are finished.
+type SolveJobService struct {
+	mazes     model.MazeService
+	timeout   time.Duration
+	retention time.Duration
+
+	queue  chan *solveJob
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu   sync.Mutex
+	jobs map[string]*solveJob
+}
+
+var _ model.SolveJobService = &SolveJobService{}
+
+type solveJob struct {
+	model.SolveJob // Guarded by SolveJobService.mu
+
+	progress *Progress
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+// NewSolveJobService starts the workers. Up to queueSize jobs can wait for a free worker,
+// each job is given the timeout to finish.
+func NewSolveJobService(mazes model.MazeService, workers, queueSize int, timeout, retention time.Duration) *SolveJobService {
+	s := &SolveJobService{
+		mazes:     mazes,
+		timeout:   timeout,
+		retention: retention,
+		queue:     make(chan *solveJob, queueSize),
+		jobs:      map[string]*solveJob{},
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
+	for i := 0; i < workers; i++ {
+		s.wg.Add(1)
+		go s.work()
+	}
+
+	return s
+}
+
+// Close cancels all jobs and waits for the workers to stop.
+func (s *SolveJobService) Close() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *SolveJobService) Submit(mazeID, userID int64, opts *model.SolveOptions) (*model.SolveJob, error) {
+	if !IsValidSteps(opts.Steps) || !IsValidMovement(opts.Movement) || (opts.BestEffort && opts.Steps != StepsMax) {
+		return nil, model.ErrInvalidInput
+	}
+
+	// Fail early if the maze doesn't exist
+	_, err := s.mazes.GetByID(mazeID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		return nil, err
+	}
+
+	j := &solveJob{
+		SolveJob: model.SolveJob{
+			ID:         id,
+			MazeID:     mazeID,
+			UserID:     userID,
+			Steps:      opts.Steps,
+			Movement:   opts.Movement,
+			BestEffort: opts.BestEffort,
+			Status:     model.JobStatusQueued,
+			CreatedAt:  time.Now().UTC(),
+		},
+		progress: &Progress{},
+	}
+	j.ctx, j.cancel = context.WithCancel(s.ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.removeExpired()
+
+	if len(s.queue) == cap(s.queue) {
+		s.removeCanceled()
+	}
+	select {
+	case s.queue <- j:
+	default:
+		j.cancel()
+		return nil, model.ErrorTooManyJobs
+	}
+	s.jobs[id] = j
+
+	return j.snapshot(), nil
+}
+
+func (s *SolveJobService) Get(id string, userID int64) (*model.SolveJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[id]
+	if !ok || j.UserID != userID {
+		return nil, model.ErrNotFound
+	}
+	return j.snapshot(), nil
+}
+
+// Cancel stops a queued or running job. Finished jobs are left as they are.
+func (s *SolveJobService) Cancel(id string, userID int64) (*model.SolveJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[id]
+	if !ok || j.UserID != userID {
+		return nil, model.ErrNotFound
+	}
+
+	if j.Status == model.JobStatusQueued || j.Status == model.JobStatusRunning {
+		j.cancel()
+		j.Status = model.JobStatusCanceled
+		j.FinishedAt = time.Now().UTC()
+	}
+	return j.snapshot(), nil
+}
+
+func (s *SolveJobService) work() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case j := <-s.queue:
+			s.run(j)
+		}
+	}
+}
+
+func (s *SolveJobService) run(j *solveJob) {
+	s.mu.Lock()
+	if j.Status != model.JobStatusQueued {
+		s.mu.Unlock()
+		return
+	}
+	j.Status = model.JobStatusRunning
+	j.StartedAt = time.Now().UTC()
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(WithProgress(j.ctx, j.progress), s.timeout)
+	defer cancel()
+	solution, err := s.mazes.Solve(ctx, j.MazeID, j.UserID, &model.SolveOptions{Steps: j.Steps, Movement: j.Movement, BestEffort: j.BestEffort})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j.cancel()
+	if j.Status == model.JobStatusCanceled {
+		return
+	}
+	j.FinishedAt = time.Now().UTC()
+	switch {
+	case err == nil:
+		j.Status = model.JobStatusDone
+		j.Path = solution.Path
+		j.Cost = solution.Cost
+		j.Optimal = solution.Optimal
+	case errors.Is(s.ctx.Err(), context.Canceled):
+		j.Status = model.JobStatusCanceled
+	default:
+		j.Status = model.JobStatusFailed
+		j.Error = err.Error()
+	}
+}
+
+// removeCanceled takes the jobs canceled while queued out of the queue, so that they don't take the place of new ones.
+// It must be called with s.mu held, Submit is the only sender, so the remaining jobs always fit back.
+func (s *SolveJobService) removeCanceled() {
+	var queued []*solveJob
+	for len(s.queue) > 0 {
+		select {
+		case j := <-s.queue:
+			if j.Status == model.JobStatusQueued {
+				queued = append(queued, j)
+			}
+		default:
+			// A worker took the last one
+		}
+	}
+	for _, j := range queued {
+		s.queue <- j
+	}
+}
+
+// removeExpired must be called with s.mu held.
+func (s *SolveJobService) removeExpired() {
+	for id, j := range s.jobs {
+		if !j.FinishedAt.IsZero() && time.Since(j.FinishedAt) > s.retention {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+// snapshot must be called with SolveJobService.mu held.
+func (j *solveJob) snapshot() *model.SolveJob {
+	res := j.SolveJob
+	res.Explored = j.progress.Explored()
+	res.Path = append([]string(nil), j.Path...)
+	return &res
+}
+
+func newJobID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
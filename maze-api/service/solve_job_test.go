@@ -0,0 +1,137 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/egurnov/maze-api/maze-api/memstore"
+	"github.com/egurnov/maze-api/maze-api/model"
+	"github.com/egurnov/maze-api/maze-api/service"
+)
+
+func TestSolveJobService(t *testing.T) {
+	newService := func(t *testing.T, workers, queueSize int, timeout time.Duration) (*service.SolveJobService, int64) {
+		mazeStore := &memstore.MazeStore{Store: memstore.New()}
+		// Scattered walls, the longest path takes far too long to find
+		id, err := mazeStore.Create(&model.Maze{Rows: 12, Cols: 12, Entrance: "A1", Walls: []string{"J1", "G2", "H2", "L2", "A3", "G3", "H3", "J3", "L4", "E5", "J6", "H9", "I9", "J9", "K9", "A10", "B10", "C10", "F10", "I10", "E11"}, UserID: 1})
+		NewWithT(t).Expect(err).ToNot(HaveOccurred())
+
+		s := service.NewSolveJobService(&service.MazeService{Store: mazeStore}, workers, queueSize, timeout, time.Hour)
+		t.Cleanup(s.Close)
+		return s, id
+	}
+
+	t.Run("done", func(t *testing.T) {
+		g := NewWithT(t)
+		s, mazeID := newService(t, 1, 1, time.Minute)
+
+		job, err := s.Submit(mazeID, 1, &model.SolveOptions{Steps: "min"})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(job.Status).To(BeElementOf(model.JobStatusQueued, model.JobStatusRunning))
+
+		g.Eventually(func() (string, error) {
+			job, err = s.Get(job.ID, 1)
+			return job.Status, err
+		}, time.Second, time.Millisecond).Should(Equal(model.JobStatusDone))
+		g.Expect(job.Path).To(HaveLen(15))
+		g.Expect(job.Cost).To(Equal(14))
+		g.Expect(job.Optimal).To(BeTrue())
+		g.Expect(job.Explored).To(BeNumerically(">", 0))
+		g.Expect(job.FinishedAt).ToNot(BeZero())
+	})
+
+	t.Run("best effort", func(t *testing.T) {
+		g := NewWithT(t)
+		s, mazeID := newService(t, 1, 1, 50*time.Millisecond)
+
+		_, err := s.Submit(mazeID, 1, &model.SolveOptions{Steps: "min", BestEffort: true})
+		g.Expect(err).To(Equal(model.ErrInvalidInput))
+
+		job, err := s.Submit(mazeID, 1, &model.SolveOptions{Steps: "max", BestEffort: true})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(job.BestEffort).To(BeTrue())
+
+		g.Eventually(func() (string, error) {
+			job, err = s.Get(job.ID, 1)
+			return job.Status, err
+		}, time.Second, time.Millisecond).Should(Equal(model.JobStatusDone))
+		g.Expect(job.Optimal).To(BeFalse())
+		g.Expect(len(job.Path)).To(BeNumerically(">", 15))
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		g := NewWithT(t)
+		s, mazeID := newService(t, 1, 1, time.Minute)
+
+		job, err := s.Submit(mazeID, 1, &model.SolveOptions{Steps: "max"})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Eventually(func() (int64, error) {
+			job, err = s.Get(job.ID, 1)
+			return job.Explored, err
+		}, time.Second, time.Millisecond).Should(BeNumerically(">", 0))
+		g.Expect(job.Status).To(Equal(model.JobStatusRunning))
+
+		job, err = s.Cancel(job.ID, 1)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(job.Status).To(Equal(model.JobStatusCanceled))
+
+		// The worker is free again
+		job, err = s.Submit(mazeID, 1, &model.SolveOptions{Steps: "min"})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Eventually(func() (string, error) {
+			job, err = s.Get(job.ID, 1)
+			return job.Status, err
+		}, time.Second, time.Millisecond).Should(Equal(model.JobStatusDone))
+	})
+
+	t.Run("queue full", func(t *testing.T) {
+		g := NewWithT(t)
+		s, mazeID := newService(t, 1, 1, time.Minute)
+
+		running, err := s.Submit(mazeID, 1, &model.SolveOptions{Steps: "max"})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Eventually(func() (string, error) {
+			job, err := s.Get(running.ID, 1)
+			return job.Status, err
+		}, time.Second, time.Millisecond).Should(Equal(model.JobStatusRunning))
+
+		queued, err := s.Submit(mazeID, 1, &model.SolveOptions{Steps: "max"})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(queued.Status).To(Equal(model.JobStatusQueued))
+
+		_, err = s.Submit(mazeID, 1, &model.SolveOptions{Steps: "max"})
+		g.Expect(err).To(Equal(model.ErrorTooManyJobs))
+
+		// Canceling the queued job frees its place
+		_, err = s.Cancel(queued.ID, 1)
+		g.Expect(err).ToNot(HaveOccurred())
+		queued, err = s.Submit(mazeID, 1, &model.SolveOptions{Steps: "max"})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(queued.Status).To(Equal(model.JobStatusQueued))
+
+		_, err = s.Submit(mazeID, 1, &model.SolveOptions{Steps: "max"})
+		g.Expect(err).To(Equal(model.ErrorTooManyJobs))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		g := NewWithT(t)
+		s, mazeID := newService(t, 1, 1, time.Minute)
+
+		_, err := s.Submit(mazeID+1, 1, &model.SolveOptions{Steps: "min"})
+		g.Expect(err).To(Equal(model.ErrNotFound))
+
+		_, err = s.Submit(mazeID, 2, &model.SolveOptions{Steps: "min"})
+		g.Expect(err).To(Equal(model.ErrNotFound))
+
+		job, err := s.Submit(mazeID, 1, &model.SolveOptions{Steps: "min"})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.Get(job.ID, 2)
+		g.Expect(err).To(Equal(model.ErrNotFound))
+		_, err = s.Cancel(job.ID, 2)
+		g.Expect(err).To(Equal(model.ErrNotFound))
+	})
+}
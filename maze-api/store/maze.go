@@ -1,7 +1,13 @@
 package store
 
 import (
+	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
 
 	"github.com/egurnov/maze-api/maze-api/model"
 )
@@ -11,54 +17,326 @@ var _ model.MazeStore = &MazeStore{&Store{}}
 type MazeStore struct{ *Store }
 
 type Maze struct {
-	ID       int64  `gorm:"primary_key;auto_increment"`
-	Rows     int    `gorm:"not null;type:int"`
-	Cols     int    `gorm:"not null;type:int"`
-	Entrance string `gorm:"not null;type:varchar(100)"`
-	Walls    string `gorm:"not null;type:varchar(500)"`
+	ID       int64   `gorm:"primary_key;auto_increment"`
+	Rows     int     `gorm:"not null;type:int"`
+	Cols     int     `gorm:"not null;type:int"`
+	Entrance string  `gorm:"not null;type:varchar(100)"`
+	Walls    string  `gorm:"not null;type:varchar(500)"` // Deprecated: comma separated walls of old records, see WallBits
+	WallBits []byte  `gorm:"column:wall_bits"`
+	ExitMode string  `gorm:"not null;type:varchar(20)"`
+	Exits    *string `gorm:"type:text"` // comma separated, NULL if the maze has no explicit exits
+	Costs    *string `gorm:"type:text"` // comma separated cell:cost pairs, NULL if all costs are 1
+	Movement string  `gorm:"not null;type:varchar(20)"`
+	Version  int64   `gorm:"not null"`
+
+	CreatedAt time.Time
 
 	UserID int64 `json:"-"`
 }
 
+// toModel fails if the stored costs are corrupt, solving the maze without them would give wrong solutions.
+func (m *Maze) toModel() (*model.Maze, error) {
+	walls := decodeLegacyWalls(m.Walls)
+	if m.WallBits != nil {
+		walls = decodeWalls(m.Rows, m.Cols, m.WallBits)
+	}
+
+	var exits []string
+	if m.Exits != nil && *m.Exits != "" {
+		exits = strings.Split(*m.Exits, ",")
+	}
+	costs, err := decodeCosts(m.Costs)
+	if err != nil {
+		return nil, fmt.Errorf("maze %d: %w", m.ID, err)
+	}
+
+	return &model.Maze{
+		ID:       m.ID,
+		Rows:     m.Rows,
+		Cols:     m.Cols,
+		Entrance: m.Entrance,
+		Walls:    walls,
+		ExitMode: m.ExitMode,
+		Exits:    exits,
+		Costs:    costs,
+		Movement: m.Movement,
+		Version:  m.Version,
+
+		CreatedAt: m.CreatedAt,
+		UserID:    m.UserID,
+	}, nil
+}
+
 func (s *MazeStore) GetByID(id, userId int64) (*model.Maze, error) {
 	var maze Maze
 	err := s.db.Where("user_id = ?", userId).First(&maze, id).Error
-	return &model.Maze{
-		ID:       maze.ID,
-		Rows:     maze.Rows,
-		Cols:     maze.Cols,
-		Entrance: maze.Entrance,
-		Walls:    strings.Split(maze.Walls, ","),
-		UserID:   maze.UserID,
-	}, wrapError(err)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return maze.toModel()
 }
 
 func (s *MazeStore) GetAll(userId int64) ([]*model.Maze, error) {
 	var mazes []*Maze
-	err := s.db.Where("user_id = ?", userId).Find(&mazes).Error
+	err := s.db.Where("user_id = ?", userId).Order("id").Find(&mazes).Error
+	if err != nil {
+		return nil, wrapError(err)
+	}
 	res := make([]*model.Maze, len(mazes))
 	for i, maze := range mazes {
-		res[i] = &model.Maze{
-			ID:       maze.ID,
-			Rows:     maze.Rows,
-			Cols:     maze.Cols,
-			Entrance: maze.Entrance,
-			Walls:    strings.Split(maze.Walls, ","),
-			UserID:   maze.UserID,
+		res[i], err = maze.toModel()
+		if err != nil {
+			return nil, err
 		}
 	}
-	return res, wrapError(err)
+	return res, nil
 }
 
 func (s *MazeStore) Create(maze *model.Maze) (int64, error) {
-	dbMaze := Maze{
+	dbMaze, err := newMaze(maze)
+	if err != nil {
+		return 0, err
+	}
+	err = s.db.Create(dbMaze).Error
+
+	return dbMaze.ID, wrapError(err)
+}
+
+func (s *MazeStore) CreateMany(mazes []*model.Maze) ([]int64, error) {
+	dbMazes := make([]*Maze, len(mazes))
+	for i, maze := range mazes {
+		var err error
+		dbMazes[i], err = newMaze(maze)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// gorm v1 has no batch inserts, a transaction makes them all or nothing
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for _, dbMaze := range dbMazes {
+			if err := tx.Create(dbMaze).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	ids := make([]int64, len(dbMazes))
+	for i, dbMaze := range dbMazes {
+		ids[i] = dbMaze.ID
+	}
+	return ids, nil
+}
+
+func newMaze(maze *model.Maze) (*Maze, error) {
+	wallBits, err := encodeWalls(maze.Rows, maze.Cols, maze.Walls)
+	if err != nil {
+		return nil, err
+	}
+
+	dbMaze := &Maze{
 		Rows:     maze.Rows,
 		Cols:     maze.Cols,
 		Entrance: maze.Entrance,
-		Walls:    strings.Join(maze.Walls, ","),
-		UserID:   maze.UserID,
+		WallBits: wallBits,
+		ExitMode: maze.ExitMode,
+		Exits:    encodeExits(maze.Exits),
+		Costs:    encodeCosts(maze.Costs),
+		Movement: maze.Movement,
+		Version:  1,
+
+		CreatedAt: maze.CreatedAt.UTC(),
+		UserID:    maze.UserID,
+	}
+	if dbMaze.CreatedAt.IsZero() {
+		dbMaze.CreatedAt = time.Now().UTC()
 	}
-	err := s.db.Create(&dbMaze).Error
+	return dbMaze, nil
+}
 
-	return dbMaze.ID, wrapError(err)
+func (s *MazeStore) Update(maze *model.Maze) error {
+	wallBits, err := encodeWalls(maze.Rows, maze.Cols, maze.Walls)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var dbMaze Maze
+		err := tx.Where("user_id = ?", maze.UserID).First(&dbMaze, maze.ID).Error
+		if err != nil {
+			return err
+		}
+
+		// The version is checked by the update itself, the maze can change after it was read
+		db := tx.Model(&dbMaze)
+		if maze.Version != 0 {
+			db = db.Where("version = ?", maze.Version)
+		}
+		db = db.Updates(map[string]interface{}{
+			"rows":      maze.Rows,
+			"cols":      maze.Cols,
+			"entrance":  maze.Entrance,
+			"walls":     "",
+			"wall_bits": wallBits,
+			"exit_mode": maze.ExitMode,
+			"exits":     encodeExits(maze.Exits),
+			"costs":     encodeCosts(maze.Costs),
+			"movement":  maze.Movement,
+			"version":   gorm.Expr("version + 1"),
+		})
+		if db.Error != nil {
+			return db.Error
+		}
+		if db.RowsAffected == 0 {
+			return model.ErrConflict
+		}
+
+		// Solutions of the old maze are no longer valid
+		return tx.Where("maze_id = ?", maze.ID).Delete(&Solution{}).Error
+	})
+
+	return wrapError(err)
+}
+
+func (s *MazeStore) Delete(id, userId int64) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("id = ? AND user_id = ?", id, userId).Delete(&Maze{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return model.ErrNotFound
+		}
+
+		return tx.Where("maze_id = ?", id).Delete(&Solution{}).Error
+	})
+
+	return wrapError(err)
+}
+
+func (s *MazeStore) List(q *model.MazeQuery) ([]*model.Maze, int64, error) {
+	rows, cols := s.db.Dialect().Quote("rows"), s.db.Dialect().Quote("cols")
+
+	db := s.db.Model(&Maze{}).Where("user_id = ?", q.UserID)
+	if q.MinRows > 0 {
+		db = db.Where(rows+" >= ?", q.MinRows)
+	}
+	if q.MaxRows > 0 {
+		db = db.Where(rows+" <= ?", q.MaxRows)
+	}
+	if q.MinCols > 0 {
+		db = db.Where(cols+" >= ?", q.MinCols)
+	}
+	if q.MaxCols > 0 {
+		db = db.Where(cols+" <= ?", q.MaxCols)
+	}
+	if !q.CreatedAfter.IsZero() {
+		db = db.Where("created_at >= ?", q.CreatedAfter.UTC())
+	}
+	if !q.CreatedBefore.IsZero() {
+		db = db.Where("created_at < ?", q.CreatedBefore.UTC())
+	}
+
+	var total int64
+	err := db.Count(&total).Error
+	if err != nil {
+		return nil, 0, wrapError(err)
+	}
+
+	// Sort by the requested column, ID breaks ties and makes the order stable
+	column := "id"
+	switch q.SortBy {
+	case model.MazeSortCreatedAt:
+		column = "created_at"
+	case model.MazeSortGridSize:
+		column = rows + "*" + cols
+	}
+	cmp, dir := ">", "ASC"
+	if q.Desc {
+		cmp, dir = "<", "DESC"
+	}
+
+	if c := q.After; c != nil {
+		switch q.SortBy {
+		case model.MazeSortCreatedAt:
+			db = db.Where(fmt.Sprintf("(%[1]s %[2]s ?) OR (%[1]s = ? AND id %[2]s ?)", column, cmp), c.CreatedAt.UTC(), c.CreatedAt.UTC(), c.ID)
+		case model.MazeSortGridSize:
+			db = db.Where(fmt.Sprintf("(%[1]s %[2]s ?) OR (%[1]s = ? AND id %[2]s ?)", column, cmp), c.GridSize, c.GridSize, c.ID)
+		default:
+			db = db.Where("id "+cmp+" ?", c.ID)
+		}
+	}
+	if column != "id" {
+		db = db.Order(column + " " + dir)
+	}
+	db = db.Order("id " + dir)
+	if q.Limit > 0 {
+		db = db.Limit(q.Limit)
+	}
+	if q.WithoutWalls {
+		db = db.Select([]string{"id", rows, cols, "entrance", "exit_mode", "exits", "costs", "movement", "version", "user_id", "created_at"})
+	}
+
+	var mazes []*Maze
+	err = db.Find(&mazes).Error
+	if err != nil {
+		return nil, 0, wrapError(err)
+	}
+
+	res := make([]*model.Maze, len(mazes))
+	for i, maze := range mazes {
+		res[i], err = maze.toModel()
+		if err != nil {
+			return nil, 0, err
+		}
+		if q.WithoutWalls {
+			res[i].Walls = nil
+		}
+	}
+	return res, total, nil
+}
+
+func encodeExits(exits []string) *string {
+	if len(exits) == 0 {
+		return nil
+	}
+	res := strings.Join(exits, ",")
+	return &res
+}
+
+// encodeCosts sorts cells so that the same costs are always stored the same way.
+func encodeCosts(costs map[string]int) *string {
+	if len(costs) == 0 {
+		return nil
+	}
+	pairs := make([]string, 0, len(costs))
+	for cell, cost := range costs {
+		pairs = append(pairs, cell+":"+strconv.Itoa(cost))
+	}
+	sort.Strings(pairs)
+	res := strings.Join(pairs, ",")
+	return &res
+}
+
+func decodeCosts(s *string) (map[string]int, error) {
+	if s == nil || *s == "" {
+		return nil, nil
+	}
+	res := map[string]int{}
+	for _, pair := range strings.Split(*s, ",") {
+		i := strings.LastIndex(pair, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid cost %q", pair)
+		}
+		cost, err := strconv.Atoi(pair[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cost %q", pair)
+		}
+		res[pair[:i]] = cost
+	}
+	return res, nil
 }
@@ -0,0 +1,229 @@
+package store
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// Migrations are stored per dialect as "<version>_<name>.up.sql" and "<version>_<name>.down.sql".
+// MySQL migrations hold one statement each, see MigrateUp.
+//
+//go:embed migrations
+var migrationsFS embed.FS
+
+var ErrSchemaOutdated = errors.New("database schema is outdated, run `maze-api migrate up`")
+
+const createSchemaMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version BIGINT NOT NULL PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	applied_at TIMESTAMP NOT NULL
+)`
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time // nil if pending
+}
+
+type schemaMigration struct {
+	Version   int64
+	Name      string
+	AppliedAt time.Time
+}
+
+// Migrations returns all known migrations for the store's dialect ordered by version.
+func (s *Store) Migrations() ([]*Migration, error) {
+	dir := path.Join("migrations", s.db.Dialect().GetName())
+	files, err := fs.ReadDir(migrationsFS, dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "no migrations for dialect "+s.db.Dialect().GetName())
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, f := range files {
+		base := strings.TrimSuffix(f.Name(), ".sql")
+		direction := strings.TrimPrefix(path.Ext(base), ".")
+		versionStr, name, ok := strings.Cut(strings.TrimSuffix(base, path.Ext(base)), "_")
+		version, err := strconv.ParseInt(versionStr, 10, 64)
+		if !ok || err != nil || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("invalid migration file name %q", f.Name())
+		}
+
+		content, err := migrationsFS.ReadFile(path.Join(dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if direction == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	res := make([]*Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		res = append(res, m)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Version < res[j].Version })
+
+	return res, nil
+}
+
+// MigrationStatus lists all known migrations and when they were applied.
+func (s *Store) MigrationStatus() ([]*MigrationStatus, error) {
+	migrations, err := s.Migrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := s.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*MigrationStatus, len(migrations))
+	for i, m := range migrations {
+		res[i] = &MigrationStatus{Version: m.Version, Name: m.Name}
+		if a, ok := applied[m.Version]; ok {
+			appliedAt := a.AppliedAt
+			res[i].AppliedAt = &appliedAt
+		}
+	}
+	return res, nil
+}
+
+// CheckSchema returns ErrSchemaOutdated if there are migrations which are not applied yet.
+func (s *Store) CheckSchema() error {
+	status, err := s.MigrationStatus()
+	if err != nil {
+		return err
+	}
+	for _, m := range status {
+		if m.AppliedAt == nil {
+			return ErrSchemaOutdated
+		}
+	}
+	return nil
+}
+
+// MigrateUp applies all pending migrations in order and returns them. Each migration runs in a transaction.
+// MySQL commits every DDL statement on its own, so the transaction doesn't protect its migrations. They hold a single
+// statement each, so a failed one isn't left half applied and can be run again.
+func (s *Store) MigrateUp() ([]*Migration, error) {
+	migrations, err := s.Migrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := s.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*Migration
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := execStatements(tx, m.Up); err != nil {
+				return err
+			}
+			return tx.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+				m.Version, m.Name, time.Now().UTC()).Error
+		})
+		if err != nil {
+			return res, errors.Wrapf(err, "migration %04d_%s failed", m.Version, m.Name)
+		}
+		res = append(res, m)
+	}
+
+	return res, nil
+}
+
+// MigrateDown reverts the latest applied migration and returns it, or nil if nothing is applied.
+func (s *Store) MigrateDown() (*Migration, error) {
+	migrations, err := s.Migrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := s.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := execStatements(tx, m.Down); err != nil {
+				return err
+			}
+			return tx.Exec("DELETE FROM schema_migrations WHERE version = ?", m.Version).Error
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "migration %04d_%s failed", m.Version, m.Name)
+		}
+		return m, nil
+	}
+
+	return nil, nil
+}
+
+func (s *Store) appliedMigrations() (map[int64]*schemaMigration, error) {
+	err := s.db.Exec(createSchemaMigrationsTable).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create schema_migrations table")
+	}
+
+	var applied []*schemaMigration
+	err = s.db.Table("schema_migrations").Find(&applied).Error
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[int64]*schemaMigration, len(applied))
+	for _, m := range applied {
+		res[m.Version] = m
+	}
+	return res, nil
+}
+
+// execStatements runs a migration script. Not all drivers allow multiple statements per query,
+// so the script is split on semicolons at line ends.
+func execStatements(tx *gorm.DB, script string) error {
+	for _, stmt := range strings.SplitAfter(script, ";\n") {
+		stmt = strings.TrimSpace(stmt)
+		if stmt == "" {
+			continue
+		}
+		if err := tx.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package store_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	storepkg "github.com/egurnov/maze-api/maze-api/store"
+)
+
+var _ = Describe("Migrations", func() {
+	Context("MySQL", func() {
+		migrationSpecs(func() (*storepkg.Store, error) {
+			return storepkg.NewMySQLStore(testDBConnString)
+		})
+	})
+
+	Context("PostgreSQL", func() {
+		migrationSpecs(func() (*storepkg.Store, error) {
+			return storepkg.NewPostgresStore(testPostgresDBConnString)
+		})
+	})
+
+	Context("SQLite", func() {
+		migrationSpecs(func() (*storepkg.Store, error) {
+			return storepkg.NewSQLiteStore(":memory:")
+		})
+	})
+})
+
+func migrationSpecs(newStore func() (*storepkg.Store, error)) {
+	var s *storepkg.Store
+
+	BeforeEach(func() {
+		var err error
+		s, err = newStore()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		_, err := s.MigrateUp()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(s.Close()).To(Succeed())
+	})
+
+	Specify("up, down and status", func() {
+		migrations, err := s.Migrations()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(migrations).ToNot(BeEmpty())
+		for i, m := range migrations {
+			Expect(m.Version).To(BeNumerically(">", 0))
+			Expect(m.Up).ToNot(BeEmpty())
+			Expect(m.Down).ToNot(BeEmpty())
+			if i > 0 {
+				Expect(m.Version).To(BeNumerically(">", migrations[i-1].Version))
+			}
+		}
+
+		By("apply everything")
+		_, err = s.MigrateUp()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(s.CheckSchema()).To(Succeed())
+
+		applied, err := s.MigrateUp()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(applied).To(BeEmpty())
+
+		By("revert the latest one")
+		reverted, err := s.MigrateDown()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reverted.Version).To(Equal(migrations[len(migrations)-1].Version))
+		Expect(s.CheckSchema()).To(MatchError(storepkg.ErrSchemaOutdated))
+
+		status, err := s.MigrationStatus()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(HaveLen(len(migrations)))
+		for _, m := range status[:len(status)-1] {
+			Expect(m.AppliedAt).ToNot(BeNil())
+		}
+		Expect(status[len(status)-1].AppliedAt).To(BeNil())
+
+		By("revert everything")
+		for range migrations[:len(migrations)-1] {
+			_, err = s.MigrateDown()
+			Expect(err).ToNot(HaveOccurred())
+		}
+		reverted, err = s.MigrateDown()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reverted).To(BeNil())
+
+		By("apply everything again")
+		applied, err = s.MigrateUp()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(applied).To(HaveLen(len(migrations)))
+		Expect(s.CheckSchema()).To(Succeed())
+	})
+}
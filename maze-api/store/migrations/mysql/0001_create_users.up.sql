@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS `users` (
+    `id` bigint NOT NULL AUTO_INCREMENT,
+    `username` varchar(100) NOT NULL,
+    `password_hash` varchar(100) NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `username` (`username`)
+);
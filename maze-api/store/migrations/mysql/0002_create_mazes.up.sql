@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS `mazes` (
+    `id` bigint NOT NULL AUTO_INCREMENT,
+    `rows` int NOT NULL,
+    `cols` int NOT NULL,
+    `entrance` varchar(100) NOT NULL,
+    `walls` varchar(500) NOT NULL,
+    `user_id` bigint,
+    PRIMARY KEY (`id`)
+);
@@ -0,0 +1 @@
+DROP INDEX `idx_mazes_user_id` ON `mazes`;
@@ -0,0 +1,3 @@
+ALTER TABLE `mazes`
+    DROP INDEX `idx_mazes_user_id_created_at`,
+    DROP COLUMN `created_at`;
@@ -0,0 +1,3 @@
+ALTER TABLE `mazes`
+    ADD COLUMN `created_at` datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    ADD INDEX `idx_mazes_user_id_created_at` (`user_id`, `created_at`);
@@ -0,0 +1,7 @@
+CREATE TABLE `solutions` (
+    `maze_id` bigint NOT NULL,
+    `steps` varchar(100) NOT NULL,
+    `path` mediumtext NOT NULL,
+    `created_at` datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (`maze_id`, `steps`)
+);
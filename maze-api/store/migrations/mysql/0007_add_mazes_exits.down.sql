@@ -0,0 +1,3 @@
+ALTER TABLE `mazes`
+    DROP COLUMN `exits`,
+    DROP COLUMN `exit_mode`;
@@ -0,0 +1,3 @@
+ALTER TABLE `mazes`
+    ADD COLUMN `exit_mode` varchar(20) NOT NULL DEFAULT '',
+    ADD COLUMN `exits` text;
@@ -0,0 +1 @@
+ALTER TABLE `mazes` ADD COLUMN `costs` mediumtext;
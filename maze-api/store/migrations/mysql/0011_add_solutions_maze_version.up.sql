@@ -0,0 +1 @@
+ALTER TABLE `solutions` ADD COLUMN `maze_version` bigint NOT NULL DEFAULT 1;
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" bigserial PRIMARY KEY,
+    "username" varchar(100) NOT NULL UNIQUE,
+    "password_hash" varchar(100) NOT NULL
+);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS "mazes" (
+    "id" bigserial PRIMARY KEY,
+    "rows" integer NOT NULL,
+    "cols" integer NOT NULL,
+    "entrance" varchar(100) NOT NULL,
+    "walls" varchar(500) NOT NULL,
+    "user_id" bigint
+);
@@ -0,0 +1 @@
+ALTER TABLE "mazes" ADD COLUMN "wall_bits" bytea;
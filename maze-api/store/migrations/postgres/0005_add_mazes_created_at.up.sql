@@ -0,0 +1,2 @@
+ALTER TABLE "mazes" ADD COLUMN "created_at" timestamptz NOT NULL DEFAULT now();
+CREATE INDEX "idx_mazes_user_id_created_at" ON "mazes" ("user_id", "created_at");
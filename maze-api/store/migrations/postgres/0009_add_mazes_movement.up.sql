@@ -0,0 +1 @@
+ALTER TABLE "mazes" ADD COLUMN "movement" varchar(20) NOT NULL DEFAULT '';
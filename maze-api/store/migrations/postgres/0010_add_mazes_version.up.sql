@@ -0,0 +1 @@
+ALTER TABLE "mazes" ADD COLUMN "version" bigint NOT NULL DEFAULT 1;
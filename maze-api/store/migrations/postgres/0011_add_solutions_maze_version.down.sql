@@ -0,0 +1 @@
+ALTER TABLE "solutions" DROP COLUMN "maze_version";
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "username" varchar(100) NOT NULL UNIQUE,
+    "password_hash" varchar(100) NOT NULL
+);
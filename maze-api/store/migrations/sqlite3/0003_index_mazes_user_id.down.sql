@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "idx_mazes_user_id";
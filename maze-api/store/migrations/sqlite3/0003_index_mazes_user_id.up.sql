@@ -0,0 +1 @@
+CREATE INDEX "idx_mazes_user_id" ON "mazes" ("user_id");
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "idx_mazes_user_id_created_at";
+ALTER TABLE "mazes" DROP COLUMN "created_at";
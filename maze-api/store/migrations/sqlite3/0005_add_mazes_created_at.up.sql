@@ -0,0 +1,4 @@
+ALTER TABLE "mazes" ADD COLUMN "created_at" datetime NOT NULL DEFAULT '1970-01-01 00:00:00+00:00';
+-- Times are compared as text, so existing rows get the format the driver writes, not the one of CURRENT_TIMESTAMP
+UPDATE "mazes" SET "created_at" = strftime('%Y-%m-%d %H:%M:%S+00:00', 'now');
+CREATE INDEX "idx_mazes_user_id_created_at" ON "mazes" ("user_id", "created_at");
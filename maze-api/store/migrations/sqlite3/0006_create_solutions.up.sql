@@ -0,0 +1,7 @@
+CREATE TABLE "solutions" (
+    "maze_id" bigint NOT NULL,
+    "steps" varchar(100) NOT NULL,
+    "path" text NOT NULL,
+    "created_at" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("maze_id", "steps")
+);
@@ -0,0 +1,2 @@
+ALTER TABLE "mazes" DROP COLUMN "exits";
+ALTER TABLE "mazes" DROP COLUMN "exit_mode";
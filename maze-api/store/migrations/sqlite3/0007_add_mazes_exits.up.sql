@@ -0,0 +1,2 @@
+ALTER TABLE "mazes" ADD COLUMN "exit_mode" varchar(20) NOT NULL DEFAULT '';
+ALTER TABLE "mazes" ADD COLUMN "exits" text;
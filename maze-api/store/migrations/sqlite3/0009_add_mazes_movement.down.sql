@@ -0,0 +1 @@
+ALTER TABLE "mazes" DROP COLUMN "movement";
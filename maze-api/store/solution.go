@@ -0,0 +1,59 @@
+package store
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/egurnov/maze-api/maze-api/model"
+)
+
+var _ model.SolutionStore = &SolutionStore{&Store{}}
+
+type SolutionStore struct{ *Store }
+
+type Solution struct {
+	MazeID      int64  `gorm:"primary_key;auto_increment:false"`
+	Steps       string `gorm:"primary_key;type:varchar(100)"`
+	MazeVersion int64  `gorm:"not null"`
+	Path        string `gorm:"not null"` // comma separated cells
+
+	CreatedAt time.Time
+}
+
+func (s *SolutionStore) Get(mazeID, mazeVersion int64, steps string) ([]string, error) {
+	var solution Solution
+	err := s.db.Where("maze_id = ? AND maze_version = ? AND steps = ?", mazeID, mazeVersion, steps).First(&solution).Error
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if solution.Path == "" {
+		return []string{}, nil
+	}
+	return strings.Split(solution.Path, ","), nil
+}
+
+// Save replaces the previous solution, if any. The maze version is checked by the statement inserting the solution,
+// so an update committed in between isn't missed. Under weaker isolation levels a concurrent update may still
+// miss the new row when deleting solutions, Get ignores it then, as it is saved for the old version.
+func (s *SolutionStore) Save(mazeID, mazeVersion int64, steps string, path []string) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("maze_id = ? AND steps = ?", mazeID, steps).Delete(&Solution{}).Error
+		if err != nil {
+			return err
+		}
+
+		res := tx.Exec("INSERT INTO solutions (maze_id, steps, maze_version, path) SELECT id, ?, version, ? FROM mazes WHERE id = ? AND version = ?",
+			steps, strings.Join(path, ","), mazeID, mazeVersion)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return model.ErrNotFound
+		}
+		return nil
+	})
+
+	return wrapError(err)
+}
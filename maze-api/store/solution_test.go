@@ -0,0 +1,96 @@
+package store_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/egurnov/maze-api/maze-api/model"
+	storepkg "github.com/egurnov/maze-api/maze-api/store"
+)
+
+var _ = Describe("SolutionStore", func() {
+	Context("MySQL", func() {
+		solutionStoreSpecs(func() (*storepkg.Store, error) {
+			return storepkg.NewMySQLStore(testDBConnString)
+		})
+	})
+
+	Context("PostgreSQL", func() {
+		solutionStoreSpecs(func() (*storepkg.Store, error) {
+			return storepkg.NewPostgresStore(testPostgresDBConnString)
+		})
+	})
+
+	Context("SQLite", func() {
+		solutionStoreSpecs(func() (*storepkg.Store, error) {
+			return storepkg.NewSQLiteStore(":memory:")
+		})
+	})
+})
+
+func solutionStoreSpecs(newStore func() (*storepkg.Store, error)) {
+	var (
+		s      *storepkg.SolutionStore
+		mazes  *storepkg.MazeStore
+		maze   *model.Maze
+		mazeID int64
+	)
+
+	BeforeEach(func() {
+		store, err := newStore()
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = store.MigrateUp()
+		Expect(err).ToNot(HaveOccurred())
+
+		s = &storepkg.SolutionStore{Store: store}
+		mazes = &storepkg.MazeStore{Store: store}
+		Expect(s.Wipe()).To(Succeed())
+
+		maze = &model.Maze{Rows: 2, Cols: 2, Entrance: "A1", Walls: []string{"B1", "B2"}, UserID: 1}
+		mazeID, err = mazes.Create(maze)
+		Expect(err).ToNot(HaveOccurred())
+		maze.ID = mazeID
+	})
+
+	AfterEach(func() {
+		Expect(s.Wipe()).To(Succeed())
+		Expect(s.Close()).To(Succeed())
+	})
+
+	Specify("full flow", func() {
+		_, err := s.Get(mazeID, 1, "min")
+		Expect(err).To(MatchError(model.ErrNotFound))
+
+		Expect(s.Save(mazeID, 1, "min", []string{"A1", "A2"})).To(Succeed())
+		Expect(s.Save(mazeID, 1, "max", []string{"A1", "A2"})).To(Succeed())
+		Expect(s.Get(mazeID, 1, "min")).To(Equal([]string{"A1", "A2"}))
+
+		By("overwrite")
+		Expect(s.Save(mazeID, 1, "min", []string{"A1"})).To(Succeed())
+		Expect(s.Get(mazeID, 1, "min")).To(Equal([]string{"A1"}))
+
+		By("unknown maze")
+		Expect(s.Save(mazeID+1, 1, "min", []string{"A1"})).To(MatchError(model.ErrNotFound))
+
+		By("update removes solutions")
+		maze.Walls = []string{"B1"}
+		Expect(mazes.Update(maze)).To(Succeed())
+		_, err = s.Get(mazeID, 2, "min")
+		Expect(err).To(MatchError(model.ErrNotFound))
+		_, err = s.Get(mazeID, 2, "max")
+		Expect(err).To(MatchError(model.ErrNotFound))
+
+		By("solutions of the old version aren't saved")
+		Expect(s.Save(mazeID, 1, "min", []string{"A1", "A2"})).To(MatchError(model.ErrNotFound))
+		Expect(s.Save(mazeID, 2, "min", []string{"A1", "A2"})).To(Succeed())
+		Expect(s.Get(mazeID, 2, "min")).To(Equal([]string{"A1", "A2"}))
+		_, err = s.Get(mazeID, 1, "min")
+		Expect(err).To(MatchError(model.ErrNotFound))
+
+		By("delete removes solutions")
+		Expect(mazes.Delete(mazeID, 1)).To(Succeed())
+		_, err = s.Get(mazeID, 2, "min")
+		Expect(err).To(MatchError(model.ErrNotFound))
+	})
+}
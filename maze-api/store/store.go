@@ -2,9 +2,14 @@ package store
 
 import (
 	"strings"
+	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/jinzhu/gorm"
-	_ "github.com/jinzhu/gorm/dialects/mysql" //nolint:golint
+	_ "github.com/jinzhu/gorm/dialects/mysql"    //nolint:golint
+	_ "github.com/jinzhu/gorm/dialects/postgres" //nolint:golint
+	_ "github.com/jinzhu/gorm/dialects/sqlite"   //nolint:golint
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 
 	"github.com/egurnov/maze-api/maze-api/model"
@@ -14,16 +19,65 @@ type Store struct {
 	db *gorm.DB
 }
 
+const (
+	mysqlErDupEntry         = 1062
+	postgresUniqueViolation = "23505"
+)
+
+// Open picks the database backend by the URL scheme:
+// "postgres://..." or "postgresql://..." for PostgreSQL, "sqlite://<path>" for SQLite,
+// "mysql://<dsn>" or a plain MySQL connection string for MySQL.
+func Open(dbURL string) (*Store, error) {
+	switch {
+	case strings.HasPrefix(dbURL, "postgres://"), strings.HasPrefix(dbURL, "postgresql://"):
+		return NewPostgresStore(dbURL)
+	case strings.HasPrefix(dbURL, "sqlite://"):
+		return NewSQLiteStore(strings.TrimPrefix(dbURL, "sqlite://"))
+	default:
+		return NewMySQLStore(strings.TrimPrefix(dbURL, "mysql://"))
+	}
+}
+
 func NewMySQLStore(filename string) (*Store, error) {
-	db, err := gorm.Open("mysql", filename)
+	// Timestamps are always stored in UTC and scanned into time.Time
+	cfg, err := mysql.ParseDSN(filename)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid mysql connection string")
+	}
+	cfg.ParseTime = true
+	cfg.Loc = time.UTC
+
+	db, err := gorm.Open("mysql", cfg.FormatDSN())
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot open mysql database")
 	}
 
-	db.AutoMigrate(
-		&User{},
-		&Maze{},
-	)
+	db.LogMode(false)
+
+	return &Store{db: db}, nil
+}
+
+// NewPostgresStore connects to PostgreSQL using either a URL or a "key=value" connection string.
+func NewPostgresStore(connString string) (*Store, error) {
+	db, err := gorm.Open("postgres", connString)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot open postgres database")
+	}
+
+	db.LogMode(false)
+
+	return &Store{db: db}, nil
+}
+
+// NewSQLiteStore opens or creates an SQLite database file. Use ":memory:" for a temporary database.
+func NewSQLiteStore(filename string) (*Store, error) {
+	db, err := gorm.Open("sqlite3", filename)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot open sqlite database")
+	}
+	// SQLite doesn't handle concurrent writers, and every connection to ":memory:" is a separate database.
+	db.DB().SetMaxOpenConns(1)
+
 	db.LogMode(false)
 
 	return &Store{db: db}, nil
@@ -33,21 +87,19 @@ func (s *Store) Close() error {
 	return s.db.Close()
 }
 
+// Wipe deletes all data, but keeps the schema.
 func (s *Store) Wipe() error {
-	err := s.db.DropTable(&User{}).Error
-	if err != nil {
-		return err
-	}
-	err = s.db.AutoMigrate(&User{}).Error
+	err := s.db.Delete(&Solution{}).Error
 	if err != nil {
 		return err
 	}
 
-	err = s.db.DropTable(&Maze{}).Error
+	err = s.db.Delete(&Maze{}).Error
 	if err != nil {
 		return err
 	}
-	err = s.db.AutoMigrate(&Maze{}).Error
+
+	err = s.db.Delete(&User{}).Error
 	if err != nil {
 		return err
 	}
@@ -59,8 +111,28 @@ func wrapError(err error) error {
 	if gorm.IsRecordNotFoundError(err) {
 		return model.ErrNotFound
 	}
-	if err != nil && strings.Contains(err.Error(), "Duplicate entry") {
+	if isUniqueViolation(err) {
 		return model.ErrUsernameAlreadyUsed
 	}
 	return err
 }
+
+// isUniqueViolation reports whether err was caused by a UNIQUE constraint.
+func isUniqueViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlErDupEntry
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == postgresUniqueViolation
+	}
+
+	// sqlite3.Error is only defined when building with cgo, so match on the message instead
+	return strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
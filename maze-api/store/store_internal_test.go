@@ -0,0 +1,214 @@
+package store
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+	"github.com/lib/pq"
+	. "github.com/onsi/gomega"
+
+	"github.com/egurnov/maze-api/maze-api/model"
+)
+
+func TestWrapError(t *testing.T) {
+	testCases := []struct {
+		desc string
+		in   error
+		exp  error
+	}{
+		{"nil", nil, nil},
+		{"not found", gorm.ErrRecordNotFound, model.ErrNotFound},
+		{"mysql duplicate entry", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'alex' for key 'username'"}, model.ErrUsernameAlreadyUsed},
+		{"mysql other error", &mysql.MySQLError{Number: 1045, Message: "Access denied"}, nil},
+		{"postgres unique violation", &pq.Error{Code: "23505", Message: "duplicate key value violates unique constraint"}, model.ErrUsernameAlreadyUsed},
+		{"postgres other error", &pq.Error{Code: "23502", Message: "null value in column"}, nil},
+		{"sqlite unique constraint", errors.New("UNIQUE constraint failed: users.username"), model.ErrUsernameAlreadyUsed},
+		{"other error", errors.New("connection refused"), nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := wrapError(tc.in)
+
+			if tc.in == nil {
+				g.Expect(err).ToNot(HaveOccurred())
+			} else if tc.exp == nil {
+				g.Expect(err).To(BeIdenticalTo(tc.in))
+			} else {
+				g.Expect(err).To(MatchError(tc.exp))
+			}
+		})
+	}
+}
+
+func TestWalls(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		rows  int
+		cols  int
+		walls []string
+	}{
+		{"no walls", 3, 3, []string{}},
+		{"all walls", 2, 3, []string{"A1", "B1", "C1", "A2", "B2", "C2"}},
+		{"example", 8, 8, []string{"C1", "G1", "A2", "C2", "E2", "G2", "C3", "E3", "B4", "C4", "E4", "F4", "G4", "B5", "E5", "B6", "D6", "E6", "G6", "H6", "B7", "D7", "G7", "B8"}},
+		{"wide", 2, 30, []string{"AD1", "A2"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			g := NewWithT(t)
+
+			bits, err := encodeWalls(tc.rows, tc.cols, tc.walls)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(bits).To(HaveLen((tc.rows*tc.cols + 7) / 8))
+			g.Expect(decodeWalls(tc.rows, tc.cols, bits)).To(Equal(tc.walls))
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := encodeWalls(2, 2, []string{"A3"})
+		g.Expect(err).To(MatchError(model.ErrInvalidInput))
+
+		_, err = encodeWalls(2, 2, []string{"1A"})
+		g.Expect(err).To(MatchError(model.ErrInvalidInput))
+	})
+}
+
+func TestLegacyWalls(t *testing.T) {
+	g := NewWithT(t)
+
+	store, err := NewSQLiteStore(":memory:")
+	g.Expect(err).ToNot(HaveOccurred())
+	defer store.Close()
+	_, err = store.MigrateUp()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	err = store.db.Exec(`INSERT INTO mazes (id, "rows", cols, entrance, walls, user_id) VALUES (1, 4, 4, 'A1', 'A4,B4,C4', 1), (2, 1, 1, 'A1', '', 1)`).Error
+	g.Expect(err).ToNot(HaveOccurred())
+
+	s := &MazeStore{Store: store}
+	maze, err := s.GetByID(1, 1)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(maze.Walls).To(Equal([]string{"A4", "B4", "C4"}))
+
+	maze, err = s.GetByID(2, 1)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(maze.Walls).To(BeEmpty())
+}
+
+func TestLegacyCreatedAt(t *testing.T) {
+	g := NewWithT(t)
+
+	store, err := NewSQLiteStore(":memory:")
+	g.Expect(err).ToNot(HaveOccurred())
+	defer store.Close()
+	_, err = store.MigrateUp()
+	g.Expect(err).ToNot(HaveOccurred())
+	for {
+		reverted, err := store.MigrateDown()
+		g.Expect(err).ToNot(HaveOccurred())
+		if reverted.Version == 5 {
+			break
+		}
+	}
+
+	// Backfilled by the migration that adds created_at
+	err = store.db.Exec(`INSERT INTO mazes (id, "rows", cols, entrance, walls, user_id) VALUES (1, 1, 1, 'A1', '', 1)`).Error
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = store.MigrateUp()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	s := &MazeStore{Store: store}
+	legacy, err := s.GetByID(1, 1)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(legacy.CreatedAt).ToNot(BeZero())
+	_, err = s.Create(&model.Maze{Rows: 1, Cols: 1, Entrance: "A1", Walls: []string{}, UserID: 1, CreatedAt: legacy.CreatedAt.Add(time.Millisecond)})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	mazes, _, err := s.List(&model.MazeQuery{UserID: 1, CreatedAfter: legacy.CreatedAt, SortBy: model.MazeSortCreatedAt})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(mazes).To(HaveLen(2))
+	g.Expect(mazes[0].ID).To(Equal(int64(1)))
+
+	mazes, _, err = s.List(&model.MazeQuery{UserID: 1, SortBy: model.MazeSortCreatedAt, Limit: 1,
+		After: &model.MazeCursor{SortBy: model.MazeSortCreatedAt, ID: 1, CreatedAt: legacy.CreatedAt}})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(mazes).To(HaveLen(1))
+	g.Expect(mazes[0].ID).To(Equal(int64(2)))
+}
+
+func TestDecodeCosts(t *testing.T) {
+	costs := func(s string) *string { return &s }
+	testCases := []struct {
+		desc   string
+		in     *string
+		exp    map[string]int
+		expErr string
+	}{
+		{desc: "null", in: nil, exp: nil},
+		{desc: "valid", in: costs("A1:5,B2:1000"), exp: map[string]int{"A1": 5, "B2": 1000}},
+		{desc: "no separator", in: costs("A1:5,B2"), expErr: `invalid cost "B2"`},
+		{desc: "invalid cost", in: costs("A1:x"), expErr: `invalid cost "A1:x"`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			g := NewWithT(t)
+
+			res, err := decodeCosts(tc.in)
+			if tc.expErr != "" {
+				g.Expect(err).To(MatchError(tc.expErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(res).To(Equal(tc.exp))
+		})
+	}
+
+	t.Run("corrupt row", func(t *testing.T) {
+		g := NewWithT(t)
+
+		store, err := NewSQLiteStore(":memory:")
+		g.Expect(err).ToNot(HaveOccurred())
+		defer store.Close()
+		_, err = store.MigrateUp()
+		g.Expect(err).ToNot(HaveOccurred())
+
+		err = store.db.Exec(`INSERT INTO mazes (id, "rows", cols, entrance, walls, costs, user_id) VALUES (1, 1, 1, 'A1', '', 'A1', 1)`).Error
+		g.Expect(err).ToNot(HaveOccurred())
+
+		s := &MazeStore{Store: store}
+		_, err = s.GetByID(1, 1)
+		g.Expect(err).To(MatchError(`maze 1: invalid cost "A1"`))
+		_, err = s.GetAll(1)
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+// MySQL commits every DDL statement on its own, a migration with several of them could be left half applied.
+func TestMySQLMigrationsHoldOneStatement(t *testing.T) {
+	g := NewWithT(t)
+
+	files, err := fs.ReadDir(migrationsFS, "migrations/mysql")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(files).ToNot(BeEmpty())
+	for _, f := range files {
+		script, err := fs.ReadFile(migrationsFS, path.Join("migrations/mysql", f.Name()))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		var statements []string
+		for _, stmt := range strings.SplitAfter(string(script), ";\n") {
+			if strings.TrimSpace(stmt) != "" {
+				statements = append(statements, stmt)
+			}
+		}
+		g.Expect(statements).To(HaveLen(1), f.Name())
+	}
+}
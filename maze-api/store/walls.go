@@ -0,0 +1,42 @@
+package store
+
+import (
+	"strings"
+
+	"github.com/egurnov/maze-api/maze-api/model"
+)
+
+// Walls are stored as a bitset with one bit per cell in row-major order, least significant bit first.
+// A 1000x1000 maze takes 125KB regardless of the number of walls.
+
+func encodeWalls(rows, cols int, walls []string) ([]byte, error) {
+	bits := make([]byte, (rows*cols+7)/8)
+	for _, wall := range walls {
+		row, col, err := model.ParseA1(wall)
+		if err != nil || row < 0 || row >= rows || col < 0 || col >= cols {
+			return nil, model.ErrInvalidInput
+		}
+		i := row*cols + col
+		bits[i/8] |= 1 << (i % 8)
+	}
+	return bits, nil
+}
+
+// decodeWalls returns walls in row-major order.
+func decodeWalls(rows, cols int, bits []byte) []string {
+	walls := []string{}
+	for i := 0; i < rows*cols && i/8 < len(bits); i++ {
+		if bits[i/8]&(1<<(i%8)) != 0 {
+			walls = append(walls, model.FormatA1(i/cols, i%cols))
+		}
+	}
+	return walls
+}
+
+// decodeLegacyWalls parses walls stored as a comma separated list before the bitset column was introduced.
+func decodeLegacyWalls(walls string) []string {
+	if walls == "" {
+		return []string{}
+	}
+	return strings.Split(walls, ",")
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"net/http/httptest"
+	"os"
 	"testing"
 	"time"
 
@@ -14,26 +15,53 @@ import (
 
 	"github.com/egurnov/maze-api/maze-api/app"
 	"github.com/egurnov/maze-api/maze-api/jwtservice"
+	"github.com/egurnov/maze-api/maze-api/memstore"
+	"github.com/egurnov/maze-api/maze-api/model"
 	"github.com/egurnov/maze-api/maze-api/service"
 	storepkg "github.com/egurnov/maze-api/maze-api/store"
 )
 
-const testDBConnString = "root:root@(localhost:3306)/maze_api_test"
+// Set TEST_DB_URL to run the suite against a database, e.g. "root:root@(localhost:3306)/maze_api_test" or "sqlite://:memory:".
+// By default an in-memory store is used.
+const testDBURLEnv = "TEST_DB_URL"
 
 func TestApp(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "E2E Suite")
 }
 
+type testStore interface {
+	Wipe() error
+	Close() error
+}
+
+func newTestStores() (testStore, model.UserStore, model.MazeStore, model.SolutionStore) {
+	dbURL := os.Getenv(testDBURLEnv)
+	if dbURL == "" {
+		store := memstore.New()
+		return store, &memstore.UserStore{Store: store}, &memstore.MazeStore{Store: store}, &memstore.SolutionStore{Store: store}
+	}
+
+	store, err := storepkg.Open(dbURL)
+	Expect(err).ToNot(HaveOccurred())
+	_, err = store.MigrateUp()
+	Expect(err).ToNot(HaveOccurred())
+	return store, &storepkg.UserStore{Store: store}, &storepkg.MazeStore{Store: store}, &storepkg.SolutionStore{Store: store}
+}
+
 var (
-	store   *storepkg.Store
-	mazeAPI *app.App
-	server  *httptest.Server
+	store      testStore
+	jobService *service.SolveJobService
+	mazeAPI    *app.App
+	server     *httptest.Server
 
 	_ = BeforeSuite(func() {
-		var err error
-		store, err = storepkg.NewMySQLStore(testDBConnString)
-		Expect(err).ToNot(HaveOccurred())
+		var (
+			userStore     model.UserStore
+			mazeStore     model.MazeStore
+			solutionStore model.SolutionStore
+		)
+		store, userStore, mazeStore, solutionStore = newTestStores()
 
 		logger := logrus.New()
 		logger.SetLevel(logrus.DebugLevel)
@@ -45,12 +73,20 @@ var (
 		engine := gin.New()
 		engine.Use(gin.LoggerWithWriter(GinkgoWriter), gin.Recovery())
 
+		mazeService := &service.MazeService{
+			Store:     mazeStore,
+			Cache:     service.NewSolutionCache(100),
+			Solutions: solutionStore,
+		}
+		jobService = service.NewSolveJobService(mazeService, 2, 10, 10*time.Second, time.Hour)
+
 		mazeAPI = &app.App{
 			Log:        logger,
 			JWTService: jwtService,
 
-			UserService: &service.UserService{Store: &storepkg.UserStore{Store: store}},
-			MazeService: &service.MazeService{Store: &storepkg.MazeStore{Store: store}},
+			UserService: &service.UserService{Store: userStore},
+			MazeService: mazeService,
+			JobService:  jobService,
 		}
 
 		mazeAPI.SetRoutes(engine)
@@ -60,6 +96,7 @@ var (
 
 	_ = AfterSuite(func() {
 		server.Close()
+		jobService.Close()
 
 		Expect(store.Close()).To(Succeed())
 	})
@@ -3,8 +3,11 @@ package test_test
 import (
 	"encoding/json"
 	"fmt"
+	"image/png"
 	"io"
 	"net/http"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -46,9 +49,11 @@ var _ = Describe("App", func() {
 		c.login("alex", "passw0rd")
 
 		var mazeId int64
+		mazeWalls := []string{"C1", "G1", "A2", "A8", "B8", "C8", "D8", "E8", "F8", "G8", "H8", "I8"}
 		By("create maze")
 		{
-			resp = c.sendReq(http.MethodPost, "/maze", `{"gridSize": "8x10", "entrance": "A1", "walls": ["C1", "G1", "A2"]}`)
+			// Walls are returned in row-major order and canonical spelling by every store
+			resp = c.sendReq(http.MethodPost, "/maze", `{"gridSize": "8x10", "entrance": "A1", "walls": ["A2", "G01", "C1", "I8", "A8", "B8", "C8", "D8", "E8", "F8", "G8", "H8"]}`)
 			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
 			var maze IDResp
 			Expect(json.NewDecoder(resp.Body).Decode(&maze)).To(Succeed())
@@ -61,7 +66,7 @@ var _ = Describe("App", func() {
 			Expect(resp.StatusCode).To(Equal(http.StatusOK))
 			var maze Maze
 			Expect(json.NewDecoder(resp.Body).Decode(&maze)).To(Succeed())
-			Expect(maze).To(Equal(Maze{ID: mazeId, GridSize: "8x10", Entrance: "A1", Walls: []string{"C1", "G1", "A2"}}))
+			Expect(maze).To(Equal(Maze{ID: mazeId, GridSize: "8x10", Entrance: "A1", Walls: mazeWalls}))
 		}
 
 		By("get all")
@@ -72,7 +77,7 @@ var _ = Describe("App", func() {
 			Expect(json.NewDecoder(resp.Body).Decode(&mazes)).To(Succeed())
 
 			Expect(mazes.Mazes).To(HaveLen(1))
-			Expect(mazes.Mazes[0]).To(Equal(Maze{ID: mazeId, GridSize: "8x10", Entrance: "A1", Walls: []string{"C1", "G1", "A2"}}))
+			Expect(mazes.Mazes[0]).To(Equal(Maze{ID: mazeId, GridSize: "8x10", Entrance: "A1", Walls: mazeWalls}))
 		}
 
 		By("other user")
@@ -95,6 +100,883 @@ var _ = Describe("App", func() {
 			Expect(io.ReadAll(resp.Body)).To(BeEquivalentTo(`{"message":"not found"}`))
 		}
 	})
+
+	Specify("Update and delete", func() {
+		resp := c.sendReq(http.MethodPost, "/user", `{"username": "alex", "password": "passw0rd"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+
+		c.login("alex", "passw0rd")
+
+		var mazeId int64
+		By("create maze")
+		{
+			resp = c.sendReq(http.MethodPost, "/maze", `{"gridSize": "4x4", "entrance": "A1", "walls": ["A4", "B4", "C4"]}`)
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			var maze IDResp
+			Expect(json.NewDecoder(resp.Body).Decode(&maze)).To(Succeed())
+			mazeId = maze.ID
+
+			resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/solution?steps=min", mazeId), "")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			var solution Solution
+			Expect(json.NewDecoder(resp.Body).Decode(&solution)).To(Succeed())
+			Expect(solution.Path).To(HaveLen(7))
+			Expect(solution.Path[6]).To(Equal("D4"))
+		}
+
+		By("replace")
+		{
+			resp = c.sendReq(http.MethodPut, fmt.Sprintf("/maze/%d", mazeId), `{"gridSize": "5x4", "entrance": "B1", "walls": ["A5", "B5", "D5"]}`)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			var maze Maze
+			Expect(json.NewDecoder(resp.Body).Decode(&maze)).To(Succeed())
+			Expect(maze).To(Equal(Maze{ID: mazeId, GridSize: "5x4", Entrance: "B1", Walls: []string{"A5", "B5", "D5"}}))
+
+			resp = c.sendReq(http.MethodPut, fmt.Sprintf("/maze/%d", mazeId), `{"gridSize": "5x4", "entrance": "B1", "walls": ["A5", "B5"]}`)
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(io.ReadAll(resp.Body)).To(ContainSubstring("invalid exit point"))
+
+			// The cached solution of the old maze is gone
+			resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/solution?steps=min", mazeId), "")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			var solution Solution
+			Expect(json.NewDecoder(resp.Body).Decode(&solution)).To(Succeed())
+			Expect(solution.Path[len(solution.Path)-1]).To(Equal("C5"))
+		}
+
+		By("patch")
+		{
+			resp = c.sendReq(http.MethodPatch, fmt.Sprintf("/maze/%d", mazeId), `{"entrance": "A1", "addWalls": ["C5"], "removeWalls": ["D5"]}`)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			var maze Maze
+			Expect(json.NewDecoder(resp.Body).Decode(&maze)).To(Succeed())
+			Expect(maze).To(Equal(Maze{ID: mazeId, GridSize: "5x4", Entrance: "A1", Walls: []string{"A5", "B5", "C5"}}))
+
+			resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d", mazeId), "")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(json.NewDecoder(resp.Body).Decode(&maze)).To(Succeed())
+			Expect(maze).To(Equal(Maze{ID: mazeId, GridSize: "5x4", Entrance: "A1", Walls: []string{"A5", "B5", "C5"}}))
+
+			resp = c.sendReq(http.MethodPatch, fmt.Sprintf("/maze/%d", mazeId), `{"entrance": "A5"}`)
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(io.ReadAll(resp.Body)).To(ContainSubstring("entrance cannot be a wall"))
+
+			// Cells match however they are spelled
+			resp = c.sendReq(http.MethodPut, fmt.Sprintf("/maze/%d", mazeId), `{"gridSize": "5x4", "entrance": "A1", "walls": ["A05", "B5", "D5"], "costs": {"C05": 3}}`)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			resp = c.sendReq(http.MethodPatch, fmt.Sprintf("/maze/%d", mazeId), `{"addWalls": ["A5", "C5"], "removeWalls": ["D05"]}`)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			body, err := io.ReadAll(resp.Body)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(body)).ToNot(ContainSubstring("costs"))
+			maze = Maze{}
+			Expect(json.Unmarshal(body, &maze)).To(Succeed())
+			Expect(maze).To(Equal(Maze{ID: mazeId, GridSize: "5x4", Entrance: "A1", Walls: []string{"A5", "B5", "C5"}}))
+		}
+
+		By("other user")
+		{
+			c2 := &client{server: server}
+
+			resp := c2.sendReq(http.MethodPost, "/user", `{"username": "alex2", "password": "passw0rd"}`)
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+
+			c2.login("alex2", "passw0rd")
+
+			resp = c2.sendReq(http.MethodPut, fmt.Sprintf("/maze/%d", mazeId), `{"gridSize": "4x4", "entrance": "A1", "walls": ["A4", "B4", "C4"]}`)
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+
+			resp = c2.sendReq(http.MethodPatch, fmt.Sprintf("/maze/%d", mazeId), `{"addWalls": ["D1"]}`)
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+
+			resp = c2.sendReq(http.MethodDelete, fmt.Sprintf("/maze/%d", mazeId), "")
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+		}
+
+		By("delete")
+		{
+			resp = c.sendReq(http.MethodDelete, fmt.Sprintf("/maze/%d", mazeId), "")
+			Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+
+			resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d", mazeId), "")
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+
+			resp = c.sendReq(http.MethodDelete, fmt.Sprintf("/maze/%d", mazeId), "")
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+		}
+	})
+
+	Specify("Pagination", func() {
+		resp := c.sendReq(http.MethodPost, "/user", `{"username": "alex", "password": "passw0rd"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+
+		c.login("alex", "passw0rd")
+
+		var ids []int64
+		for _, size := range []int{4, 6, 5} {
+			resp = c.sendReq(http.MethodPost, "/maze", fmt.Sprintf(`{"gridSize": "%dx2", "entrance": "A1", "walls": ["A%d"]}`, size, size))
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			var maze IDResp
+			Expect(json.NewDecoder(resp.Body).Decode(&maze)).To(Succeed())
+			ids = append(ids, maze.ID)
+		}
+
+		By("first page")
+		resp = c.sendReq(http.MethodGet, "/maze?limit=2&sort=-gridSize&fields=gridSize", "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var page Mazes
+		Expect(json.NewDecoder(resp.Body).Decode(&page)).To(Succeed())
+		Expect(page.Total).To(Equal(int64(3)))
+		Expect(page.NextCursor).ToNot(BeEmpty())
+		Expect(page.Mazes).To(Equal([]Maze{{ID: ids[1], GridSize: "6x2"}, {ID: ids[2], GridSize: "5x2"}}))
+
+		By("last page")
+		resp = c.sendReq(http.MethodGet, "/maze?limit=2&sort=-gridSize&fields=gridSize,walls&after="+page.NextCursor, "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		page = Mazes{}
+		Expect(json.NewDecoder(resp.Body).Decode(&page)).To(Succeed())
+		Expect(page.Total).To(Equal(int64(3)))
+		Expect(page.NextCursor).To(BeEmpty())
+		Expect(page.Mazes).To(Equal([]Maze{{ID: ids[0], GridSize: "4x2", Walls: []string{"A4"}}}))
+
+		By("filters")
+		resp = c.sendReq(http.MethodGet, "/maze?minRows=5&maxRows=5", "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		page = Mazes{}
+		Expect(json.NewDecoder(resp.Body).Decode(&page)).To(Succeed())
+		Expect(page.Total).To(Equal(int64(1)))
+		Expect(page.Mazes).To(Equal([]Maze{{ID: ids[2], GridSize: "5x2", Entrance: "A1", Walls: []string{"A5"}}}))
+
+		By("invalid parameters")
+		for _, query := range []string{"limit=-1", "limit=1001", "sort=walls", "fields=password", "after=garbage", "createdAfter=yesterday"} {
+			resp = c.sendReq(http.MethodGet, "/maze?"+query, "")
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest), query)
+		}
+
+		By("cursor for another sort order")
+		resp = c.sendReq(http.MethodGet, "/maze?limit=1", "")
+		page = Mazes{}
+		Expect(json.NewDecoder(resp.Body).Decode(&page)).To(Succeed())
+		resp = c.sendReq(http.MethodGet, "/maze?limit=1&sort=-id&after="+page.NextCursor, "")
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+	})
+
+	Specify("Exits", func() {
+		resp := c.sendReq(http.MethodPost, "/user", `{"username": "alex", "password": "passw0rd"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		c.login("alex", "passw0rd")
+
+		By("explicit exits")
+		resp = c.sendReq(http.MethodPost, "/maze", `{"gridSize": "4x4", "entrance": "A1", "walls": ["B1", "B2"], "exits": ["D1", "A4"]}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		var maze IDResp
+		Expect(json.NewDecoder(resp.Body).Decode(&maze)).To(Succeed())
+
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var exits MazeExits
+		Expect(json.NewDecoder(resp.Body).Decode(&exits)).To(Succeed())
+		Expect(exits).To(Equal(MazeExits{ExitMode: "explicit", Exits: []string{"D1", "A4"}}))
+
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/solution?steps=min", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var solution Solution
+		Expect(json.NewDecoder(resp.Body).Decode(&solution)).To(Succeed())
+		Expect(solution).To(Equal(Solution{Path: []string{"A1", "A2", "A3", "A4"}, Exit: "A4", Cost: 3}))
+
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/solution?steps=max", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(json.NewDecoder(resp.Body).Decode(&solution)).To(Succeed())
+		Expect(solution.Exit).To(Equal("D1"))
+
+		By("any exit")
+		resp = c.sendReq(http.MethodPost, "/maze", `{"gridSize": "4x4", "entrance": "A1", "walls": ["A4", "B4"], "exitMode": "any-exit"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+
+		resp = c.sendReq(http.MethodPost, "/maze", `{"gridSize": "4x4", "entrance": "A1", "walls": ["A4", "B4"]}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		Expect(io.ReadAll(resp.Body)).To(ContainSubstring("invalid exit point"))
+
+		By("invalid exits")
+		for _, body := range []string{
+			`{"gridSize": "4x4", "entrance": "A1", "exitMode": "none"}`,
+			`{"gridSize": "4x4", "entrance": "A1", "exitMode": "explicit"}`,
+			`{"gridSize": "4x4", "entrance": "A1", "exits": ["B2"]}`,
+			`{"gridSize": "4x4", "entrance": "A1", "exitMode": "any-exit", "exits": ["D4"]}`,
+		} {
+			resp = c.sendReq(http.MethodPost, "/maze", body)
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest), body)
+		}
+	})
+
+	Specify("Costs", func() {
+		resp := c.sendReq(http.MethodPost, "/user", `{"username": "alex", "password": "passw0rd"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		c.login("alex", "passw0rd")
+
+		resp = c.sendReq(http.MethodPost, "/maze", `{"gridSize": "5x3", "entrance": "A1", "walls": ["B2", "B3", "A5", "B5"], "costs": {"A3": 10}}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		var maze IDResp
+		Expect(json.NewDecoder(resp.Body).Decode(&maze)).To(Succeed())
+
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(io.ReadAll(resp.Body)).To(ContainSubstring(`"costs":{"A3":10}`))
+
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/solution?steps=cheapest", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var solution Solution
+		Expect(json.NewDecoder(resp.Body).Decode(&solution)).To(Succeed())
+		Expect(solution).To(Equal(Solution{Path: []string{"A1", "B1", "C1", "C2", "C3", "C4", "C5"}, Exit: "C5", Cost: 6}))
+
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/solution?steps=min", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(json.NewDecoder(resp.Body).Decode(&solution)).To(Succeed())
+		Expect(solution.Cost).To(BeNumerically(">=", 6))
+
+		By("a new wall removes the cost of its cell")
+		resp = c.sendReq(http.MethodPatch, fmt.Sprintf("/maze/%d", maze.ID), `{"addWalls": ["A3"]}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(body)).To(ContainSubstring(`"A3"`))
+		Expect(string(body)).ToNot(ContainSubstring(`"costs"`))
+
+		By("invalid costs")
+		for _, costs := range []string{`{"B2": 5}`, `{"A3": 0}`, `{"A3": 1001}`, `{"Z9": 1}`} {
+			resp = c.sendReq(http.MethodPost, "/maze", `{"gridSize": "5x3", "entrance": "A1", "walls": ["B2", "B3", "A5", "B5"], "costs": `+costs+`}`)
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest), costs)
+		}
+	})
+
+	Specify("Movement", func() {
+		resp := c.sendReq(http.MethodPost, "/user", `{"username": "alex", "password": "passw0rd"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		c.login("alex", "passw0rd")
+
+		By("the exit is reachable diagonally only")
+		resp = c.sendReq(http.MethodPost, "/maze", `{"gridSize": "3x3", "entrance": "A1", "walls": ["B1", "A2", "A3", "C3"]}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+		resp = c.sendReq(http.MethodPost, "/maze", `{"gridSize": "3x3", "entrance": "A1", "walls": ["B1", "A2", "A3", "C3"], "movement": "8-cut"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		var maze IDResp
+		Expect(json.NewDecoder(resp.Body).Decode(&maze)).To(Succeed())
+
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(io.ReadAll(resp.Body)).To(ContainSubstring(`"movement":"8-cut"`))
+
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/solution?steps=min", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var solution Solution
+		Expect(json.NewDecoder(resp.Body).Decode(&solution)).To(Succeed())
+		Expect(solution.Path).To(Equal([]string{"A1", "B2", "B3"}))
+
+		By("overridden when solving")
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/solution?steps=min&movement=knight", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(json.NewDecoder(resp.Body).Decode(&solution)).To(Succeed())
+		Expect(solution.Path).To(Equal([]string{"A1", "B3"}))
+
+		By("no corner cuts, no solution")
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/solution?steps=min&movement=8", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		Expect(io.ReadAll(resp.Body)).To(ContainSubstring("no solution"))
+
+		By("invalid movement")
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/solution?steps=min&movement=queen", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+		resp = c.sendReq(http.MethodPost, "/maze", `{"gridSize": "3x3", "entrance": "A1", "movement": "queen"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+	})
+
+	Specify("All shortest paths and stats", func() {
+		resp := c.sendReq(http.MethodPost, "/user", `{"username": "alex", "password": "passw0rd"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		c.login("alex", "passw0rd")
+
+		resp = c.sendReq(http.MethodPost, "/maze", `{"gridSize": "3x3", "entrance": "A1", "exits": ["C3"]}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		var maze IDResp
+		Expect(json.NewDecoder(resp.Body).Decode(&maze)).To(Succeed())
+
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/solution?steps=min&all=true", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var paths ShortestPaths
+		Expect(json.NewDecoder(resp.Body).Decode(&paths)).To(Succeed())
+		Expect(paths.Paths).To(HaveLen(6))
+		Expect(paths.Path).To(Equal(paths.Paths[0]))
+		Expect(paths.Exit).To(Equal("C3"))
+		Expect(paths.Cost).To(Equal(4))
+		Expect(paths.Total).To(Equal(6))
+
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/solution?steps=min&all=true&limit=2", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(json.NewDecoder(resp.Body).Decode(&paths)).To(Succeed())
+		Expect(paths.Paths).To(HaveLen(2))
+		Expect(paths.Total).To(Equal(6))
+
+		for _, query := range []string{"steps=max&all=true", "steps=min&all=true&limit=1001", "steps=min&all=maybe"} {
+			resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/solution?%s", maze.ID, query), "")
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest), query)
+		}
+
+		By("stats")
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/stats", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var stats MazeStats
+		Expect(json.NewDecoder(resp.Body).Decode(&stats)).To(Succeed())
+		Expect(stats).To(Equal(MazeStats{ShortestPathLength: 5, ShortestPaths: 6, SimplePaths: 12}))
+
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/stats?movement=knight", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(json.NewDecoder(resp.Body).Decode(&stats)).To(Succeed())
+		Expect(stats.ShortestPathLength).To(Equal(5))
+
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/stats?movement=queen", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/stats", maze.ID+1), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+	})
+
+	Specify("Analysis", func() {
+		resp := c.sendReq(http.MethodPost, "/user", `{"username": "alex", "password": "passw0rd"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		c.login("alex", "passw0rd")
+
+		resp = c.sendReq(http.MethodPost, "/maze", `{"gridSize": "4x4", "entrance": "A1", "walls": ["C1", "A2", "C2", "D3", "B4"], "exits": ["D4"]}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		var maze IDResp
+		Expect(json.NewDecoder(resp.Body).Decode(&maze)).To(Succeed())
+
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/analysis", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var analysis MazeAnalysis
+		Expect(json.NewDecoder(resp.Body).Decode(&analysis)).To(Succeed())
+		Expect(analysis).To(Equal(MazeAnalysis{
+			ReachableCells:     9,
+			UnreachableRegions: 1,
+			UnreachableCells:   2,
+			DeadEnds:           1,
+			BranchingFactor:    1,
+			Chokepoints:        []string{"B1", "B2", "B3", "C3", "C4"},
+			ShortestPathLength: 7,
+			LongestPathLength:  7,
+			LongestPathOptimal: true,
+			Difficulty:         39,
+		}))
+
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/analysis?movement=queen", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/analysis", maze.ID+1), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+	})
+
+	Specify("Solve without saving", func() {
+		body := `{"gridSize": "3x3", "entrance": "A1", "walls": ["B2"], "exits": ["C3"]}`
+		resp := c.sendReq(http.MethodPost, "/solve?steps=min", body)
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+
+		resp = c.sendReq(http.MethodPost, "/user", `{"username": "alex", "password": "passw0rd"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		c.login("alex", "passw0rd")
+
+		resp = c.sendReq(http.MethodPost, "/solve?steps=min", body)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var solution SolutionInfo
+		Expect(json.NewDecoder(resp.Body).Decode(&solution)).To(Succeed())
+		Expect(solution.Path).To(HaveLen(5))
+		Expect(solution.Exit).To(Equal("C3"))
+		Expect(solution.Cost).To(Equal(4))
+		Expect(solution.Optimal).To(BeTrue())
+
+		// Orthogonal paths have 5 cells at most
+		resp = c.sendReq(http.MethodPost, "/solve?steps=max&movement=8-cut", body)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(json.NewDecoder(resp.Body).Decode(&solution)).To(Succeed())
+		Expect(solution.Path).To(HaveLen(7))
+
+		resp = c.sendReq(http.MethodPost, "/solve?steps=cheapest", `{"gridSize": "3x3", "entrance": "A1", "walls": ["B2"], "exits": ["C3"], "costs": {"B1": 10}}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(json.NewDecoder(resp.Body).Decode(&solution)).To(Succeed())
+		Expect(solution.Path).To(Equal([]string{"A1", "A2", "A3", "B3", "C3"}))
+
+		By("nothing is stored")
+		resp = c.sendReq(http.MethodGet, "/maze", "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var mazes Mazes
+		Expect(json.NewDecoder(resp.Body).Decode(&mazes)).To(Succeed())
+		Expect(mazes.Mazes).To(BeEmpty())
+
+		By("invalid input")
+		resp = c.sendReq(http.MethodPost, "/solve?steps=longest", body)
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		resp = c.sendReq(http.MethodPost, "/solve?steps=min", `{"gridSize": "3x3", "entrance": "B2", "walls": ["B2"]}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		Expect(io.ReadAll(resp.Body)).To(ContainSubstring("entrance cannot be a wall"))
+		resp = c.sendReq(http.MethodPost, "/solve?steps=min", `{"gridSize": "3x3", "entrance": "A1", "walls": ["A2", "B2", "C2"], "exits": ["C3"]}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+	})
+
+	Specify("Import and export", func() {
+		resp := c.sendReq(http.MethodPost, "/user", `{"username": "alex", "password": "passw0rd"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		c.login("alex", "passw0rd")
+
+		By("JSON array")
+		resp = c.sendReq(http.MethodPost, "/maze/import", `[
+			{"gridSize": "4x4", "entrance": "A1", "walls": ["A4", "B4", "C4"]},
+			{"gridSize": "3x3", "entrance": "A1", "walls": ["B2"], "exits": ["C3"], "movement": "8"}
+		]`)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var imported ImportResult
+		Expect(json.NewDecoder(resp.Body).Decode(&imported)).To(Succeed())
+		Expect(imported.Imported).To(Equal(2))
+		Expect(imported.Failed).To(Equal(0))
+		Expect(imported.Items).To(HaveLen(2))
+		Expect(imported.Items[0].ID).ToNot(BeZero())
+		Expect(imported.Items[1].ID).ToNot(BeZero())
+
+		By("NDJSON with an invalid maze")
+		ndjson := `{"gridSize": "2x2", "entrance": "A1", "walls": ["B2"]}
+{"gridSize": "2x2", "entrance": "A1", "walls": ["A1"]}
+{"gridSize": "2x2", "entrance": "A1", "movement": "queen"}
+`
+		resp = c.sendReq(http.MethodPost, "/maze/import?atomic=true", ndjson)
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		imported = ImportResult{}
+		Expect(json.NewDecoder(resp.Body).Decode(&imported)).To(Succeed())
+		Expect(imported.Imported).To(Equal(0))
+		Expect(imported.Failed).To(Equal(2))
+		Expect(imported.Items[0].Error).To(BeEmpty())
+		Expect(imported.Items[1].Error).To(ContainSubstring("entrance cannot be a wall"))
+		Expect(imported.Items[2].Error).ToNot(BeEmpty())
+
+		resp = c.sendReq(http.MethodPost, "/maze/import", ndjson)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		imported = ImportResult{}
+		Expect(json.NewDecoder(resp.Body).Decode(&imported)).To(Succeed())
+		Expect(imported.Imported).To(Equal(1))
+		Expect(imported.Failed).To(Equal(2))
+		Expect(imported.Items[0].ID).ToNot(BeZero())
+		Expect(imported.Items[1].ID).To(BeZero())
+
+		for _, body := range []string{"", "[]", `{"gridSize": `, `[{"gridSize": "2x2", "entrance": "A1"}] {}`, `[{"gridSize": "2x2", "entrance": "A1"}`, `[{} {}]`} {
+			resp = c.sendReq(http.MethodPost, "/maze/import", body)
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest), body)
+		}
+		resp = c.sendReq(http.MethodPost, "/maze/import?atomic=maybe", ndjson)
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+		By("limits")
+		resp = c.sendReq(http.MethodPost, "/maze/import", "["+strings.Repeat(`{"gridSize": "2x2", "entrance": "A1", "walls": ["B2"]},`, 1001)+"]")
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		Expect(io.ReadAll(resp.Body)).To(ContainSubstring("at most 1000 mazes can be imported at once"))
+
+		resp = c.sendReq(http.MethodPost, "/maze/import", "["+strings.Repeat(" ", 16<<20)+"]")
+		Expect(resp.StatusCode).To(Equal(http.StatusRequestEntityTooLarge))
+
+		By("export")
+		resp = c.sendReq(http.MethodGet, "/maze/export", "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Header.Get("Content-Type")).To(Equal("application/x-ndjson"))
+		exported, err := io.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+		lines := strings.Split(strings.TrimSpace(string(exported)), "\n")
+		Expect(lines).To(HaveLen(3))
+		var maze Maze
+		Expect(json.Unmarshal([]byte(lines[1]), &maze)).To(Succeed())
+		Expect(maze.GridSize).To(Equal("3x3"))
+		Expect(maze.Walls).To(Equal([]string{"B2"}))
+		Expect(lines[1]).To(ContainSubstring(`"movement":"8"`))
+
+		By("round trip")
+		c2 := &client{server: server}
+		resp = c2.sendReq(http.MethodPost, "/user", `{"username": "alex2", "password": "passw0rd"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		c2.login("alex2", "passw0rd")
+
+		resp = c2.sendReq(http.MethodPost, "/maze/import?atomic=true", string(exported))
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		imported = ImportResult{}
+		Expect(json.NewDecoder(resp.Body).Decode(&imported)).To(Succeed())
+		Expect(imported.Imported).To(Equal(3))
+
+		resp = c2.sendReq(http.MethodGet, "/maze/export", "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		reexported, err := io.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strings.Split(strings.TrimSpace(string(reexported)), "\n")).To(HaveLen(3))
+
+		By("export in batches")
+		// Mazes are exported in batches of 100
+		resp = c2.sendReq(http.MethodPost, "/maze/import", strings.Repeat(`{"gridSize": "2x2", "entrance": "A1", "walls": ["B2"]}`+"\n", 100))
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		resp = c2.sendReq(http.MethodGet, "/maze/export", "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		reexported, err = io.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+		lines = strings.Split(strings.TrimSpace(string(reexported)), "\n")
+		Expect(lines).To(HaveLen(103))
+		var prevID int64
+		for _, line := range lines {
+			var maze Maze
+			Expect(json.Unmarshal([]byte(line), &maze)).To(Succeed())
+			Expect(maze.ID).To(BeNumerically(">", prevID))
+			prevID = maze.ID
+		}
+	})
+
+	Specify("Text maze", func() {
+		resp := c.sendReq(http.MethodPost, "/user", `{"username": "alex", "password": "passw0rd"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		c.login("alex", "passw0rd")
+		text := map[string]string{"Content-Type": "text/plain"}
+
+		resp = c.sendReqWithHeaders(http.MethodPost, "/maze", "E#..\n.#..\n....\n###.\n", text)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		var created IDResp
+		Expect(json.NewDecoder(resp.Body).Decode(&created)).To(Succeed())
+
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d", created.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var maze Maze
+		Expect(json.NewDecoder(resp.Body).Decode(&maze)).To(Succeed())
+		Expect(maze).To(Equal(Maze{ID: created.ID, GridSize: "4x4", Entrance: "A1", Walls: []string{"B1", "B2", "A4", "B4", "C4"}}))
+
+		By("print and create")
+		resp = c.sendReqWithHeaders(http.MethodGet, fmt.Sprintf("/maze/%d/print", created.ID), "", map[string]string{"Accept": "text/plain"})
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		printed, err := io.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+
+		resp = c.sendReqWithHeaders(http.MethodPost, "/maze?entrance=A1", string(printed), text)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		var copied IDResp
+		Expect(json.NewDecoder(resp.Body).Decode(&copied)).To(Succeed())
+
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d", copied.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(json.NewDecoder(resp.Body).Decode(&maze)).To(Succeed())
+		Expect(maze).To(Equal(Maze{ID: copied.ID, GridSize: "4x4", Entrance: "A1", Walls: []string{"B1", "B2", "A4", "B4", "C4"}}))
+
+		By("ascii print with labels and create")
+		for _, query := range []string{"style=ascii", "solution=min"} {
+			resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/print?%s", created.ID, query), "")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			printed, err := io.ReadAll(resp.Body)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(printed)).To(HavePrefix("  ABCD\n1 E#"))
+
+			resp = c.sendReqWithHeaders(http.MethodPost, "/maze", string(printed), text)
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated), query)
+			Expect(json.NewDecoder(resp.Body).Decode(&copied)).To(Succeed())
+
+			resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d", copied.ID), "")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(json.NewDecoder(resp.Body).Decode(&maze)).To(Succeed())
+			Expect(maze).To(Equal(Maze{ID: copied.ID, GridSize: "4x4", Entrance: "A1", Walls: []string{"B1", "B2", "A4", "B4", "C4"}}), query)
+		}
+
+		By("invalid input")
+		for _, tc := range []struct{ path, body, message string }{
+			{"/maze", string(printed), "no entrance"},
+			{"/maze?entrance=A1", "E#..\n.#..\n....\n###.\n", "the entrance is marked with E and given as a parameter"},
+			{"/maze", "E#.\n.#\n", "row 2: 2 cells, the first row has 3"},
+			{"/maze", "E#.\n###\n", "invalid exit point"},
+		} {
+			resp = c.sendReqWithHeaders(http.MethodPost, tc.path, tc.body, text)
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest), tc.body)
+			Expect(io.ReadAll(resp.Body)).To(ContainSubstring(tc.message), tc.body)
+		}
+	})
+
+	Specify("Path verification", func() {
+		resp := c.sendReq(http.MethodPost, "/user", `{"username": "alex", "password": "passw0rd"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		c.login("alex", "passw0rd")
+
+		resp = c.sendReq(http.MethodPost, "/maze", `{"gridSize": "4x4", "entrance": "A1", "walls": ["B1", "B2", "A4", "B4", "C4"]}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		var maze IDResp
+		Expect(json.NewDecoder(resp.Body).Decode(&maze)).To(Succeed())
+
+		resp = c.sendReq(http.MethodPost, fmt.Sprintf("/maze/%d/verify", maze.ID), `{"path": ["A1", "A2", "A3", "B3", "C3", "C2", "C1", "D1", "D2", "D3", "D4"]}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(io.ReadAll(resp.Body)).To(MatchJSON(`{"valid": true, "length": 11, "cost": 10, "shortestPathLength": 7, "extraSteps": 4, "errors": []}`))
+
+		resp = c.sendReq(http.MethodPost, fmt.Sprintf("/maze/%d/verify", maze.ID), `{"path": ["A1", "B1", "A3", "B3", "C3", "D3"]}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(io.ReadAll(resp.Body)).To(MatchJSON(`{"valid": false, "length": 6, "shortestPathLength": 7, "extraSteps": 0, "errors": [
+			{"index": 1, "cell": "B1", "message": "wall"},
+			{"index": 5, "cell": "D3", "message": "the path must end at an exit"}
+		]}`))
+
+		resp = c.sendReq(http.MethodPost, fmt.Sprintf("/maze/%d/verify?movement=8-cut", maze.ID), `{"path": ["A1", "A2", "B3", "C3", "D4"]}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(io.ReadAll(resp.Body)).To(MatchJSON(`{"valid": true, "length": 5, "cost": 4, "shortestPathLength": 5, "extraSteps": 0, "errors": []}`))
+
+		resp = c.sendReq(http.MethodPost, fmt.Sprintf("/maze/%d/verify", maze.ID), `{}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		resp = c.sendReq(http.MethodPost, fmt.Sprintf("/maze/%d/verify?movement=queen", maze.ID), `{"path": ["A1"]}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		resp = c.sendReq(http.MethodPost, fmt.Sprintf("/maze/%d/verify", maze.ID+1), `{"path": ["A1"]}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+	})
+
+	Specify("Directions", func() {
+		resp := c.sendReq(http.MethodPost, "/user", `{"username": "alex", "password": "passw0rd"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		c.login("alex", "passw0rd")
+
+		resp = c.sendReq(http.MethodPost, "/maze", `{"gridSize": "4x4", "entrance": "A1", "walls": ["B1", "B2", "A4", "B4", "C4"]}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		var maze IDResp
+		Expect(json.NewDecoder(resp.Body).Decode(&maze)).To(Succeed())
+
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/solution?steps=min&format=directions", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var solution DirectionsSolution
+		Expect(json.NewDecoder(resp.Body).Decode(&solution)).To(Succeed())
+		Expect(solution.Path).To(Equal([]string{"A1", "A2", "A3", "B3", "C3", "D3", "D4"}))
+		Expect(solution.Directions).To(Equal("D2 R3 D1"))
+		Expect(solution.Compass).To(Equal("S2 E3 S1"))
+
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/solution?steps=min", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		solution = DirectionsSolution{}
+		Expect(json.NewDecoder(resp.Body).Decode(&solution)).To(Succeed())
+		Expect(solution.Directions).To(BeEmpty())
+
+		resp = c.sendReq(http.MethodPost, "/solve?steps=min&movement=8-cut&format=directions", `{"gridSize": "4x4", "entrance": "A1", "walls": ["B1", "B2", "A4", "B4", "C4"]}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		solution = DirectionsSolution{}
+		Expect(json.NewDecoder(resp.Body).Decode(&solution)).To(Succeed())
+		Expect(solution.Directions).To(Equal("D1 DR1 R1 DR1"))
+		Expect(solution.Compass).To(Equal("S1 SE1 E1 SE1"))
+
+		By("verification")
+		resp = c.sendReq(http.MethodPost, fmt.Sprintf("/maze/%d/verify", maze.ID), `{"directions": "D2 R3 D1"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(io.ReadAll(resp.Body)).To(MatchJSON(`{"valid": true, "length": 7, "cost": 6, "shortestPathLength": 7, "extraSteps": 0, "errors": []}`))
+
+		resp = c.sendReq(http.MethodPost, fmt.Sprintf("/maze/%d/verify", maze.ID), `{"directions": "S S E"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(io.ReadAll(resp.Body)).To(MatchJSON(`{"valid": false, "length": 4, "shortestPathLength": 7, "extraSteps": 0, "errors": [
+			{"index": 3, "cell": "B3", "message": "the path must end at an exit"}
+		]}`))
+
+		By("invalid parameters")
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/solution?steps=min&format=json", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		resp = c.sendReq(http.MethodPost, fmt.Sprintf("/maze/%d/verify", maze.ID), `{"directions": "X1"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		resp = c.sendReq(http.MethodPost, fmt.Sprintf("/maze/%d/verify", maze.ID), `{"directions": "L1"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		resp = c.sendReq(http.MethodPost, fmt.Sprintf("/maze/%d/verify", maze.ID), `{"path": ["A1"], "directions": "D1"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		resp = c.sendReq(http.MethodPost, fmt.Sprintf("/maze/%d/verify", maze.ID+1), `{"directions": "D1"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+	})
+
+	Specify("Best effort", func() {
+		resp := c.sendReq(http.MethodPost, "/user", `{"username": "alex", "password": "passw0rd"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		c.login("alex", "passw0rd")
+
+		resp = c.sendReq(http.MethodPost, "/maze", `{"gridSize": "8x8", "entrance": "A1", "walls": ["C1", "G1", "A2", "C2", "D2", "E2", "G2", "E3", "B4", "C4", "E4", "F4", "G4", "C6", "E5", "B6", "D6", "E6", "G6", "H6", "B7", "D7", "G7", "B8"]}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		var maze IDResp
+		Expect(json.NewDecoder(resp.Body).Decode(&maze)).To(Succeed())
+
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/solution?steps=max&bestEffort=true", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var solution SolutionInfo
+		Expect(json.NewDecoder(resp.Body).Decode(&solution)).To(Succeed())
+		Expect(solution.Path).To(HaveLen(14))
+		Expect(solution.Optimal).To(BeTrue())
+		Expect(solution.Explored).To(BeNumerically(">", 0))
+		Expect(solution.ElapsedMs).To(BeNumerically(">=", 0))
+
+		By("stored solution")
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/solution?steps=max", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(json.NewDecoder(resp.Body).Decode(&solution)).To(Succeed())
+		Expect(solution.Path).To(HaveLen(14))
+		Expect(solution.Optimal).To(BeTrue())
+		Expect(solution.Explored).To(BeZero())
+
+		By("invalid parameters")
+		for _, query := range []string{"steps=min&bestEffort=true", "steps=cheapest&bestEffort=true", "steps=max&bestEffort=maybe"} {
+			resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/solution?%s", maze.ID, query), "")
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest), query)
+		}
+	})
+
+	Specify("Generate", func() {
+		resp := c.sendReq(http.MethodPost, "/user", `{"username": "alex", "password": "passw0rd"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		c.login("alex", "passw0rd")
+
+		By("same seed, same maze")
+		var mazes [2]GeneratedMaze
+		for i := range mazes {
+			resp = c.sendReq(http.MethodPost, "/maze/generate", `{"gridSize": "15x20", "algorithm": "wilson", "seed": 42, "difficulty": "hard"}`)
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			Expect(json.NewDecoder(resp.Body).Decode(&mazes[i])).To(Succeed())
+		}
+		Expect(mazes[0].ID).ToNot(Equal(mazes[1].ID))
+		Expect(mazes[0].Walls).To(Equal(mazes[1].Walls))
+		Expect(mazes[0].GridSize).To(Equal("15x20"))
+		Expect(mazes[0].Entrance).To(Equal("A1"))
+		Expect(mazes[0].Seed).To(Equal(int64(42)))
+
+		By("stored for the user")
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d", mazes[0].ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var maze Maze
+		Expect(json.NewDecoder(resp.Body).Decode(&maze)).To(Succeed())
+		Expect(maze).To(Equal(mazes[0].Maze))
+
+		resp = c.sendReq(http.MethodGet, fmt.Sprintf("/maze/%d/solution?steps=max", mazes[0].ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		By("defaults")
+		resp = c.sendReq(http.MethodPost, "/maze/generate", `{"gridSize": "10x10"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		var generated GeneratedMaze
+		Expect(json.NewDecoder(resp.Body).Decode(&generated)).To(Succeed())
+		Expect(generated.Algorithm).To(Equal("backtracker"))
+		Expect(generated.Difficulty).To(Equal("medium"))
+
+		By("invalid parameters")
+		for _, body := range []string{`{}`, `{"gridSize": "1x10"}`, `{"gridSize": "10x10", "algorithm": "eller"}`, `{"gridSize": "10x10", "difficulty": "insane"}`} {
+			resp = c.sendReq(http.MethodPost, "/maze/generate", body)
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest), body)
+		}
+	})
+
+	Specify("Print", func() {
+		resp := c.sendReq(http.MethodPost, "/user", `{"username": "alex", "password": "passw0rd"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		c.login("alex", "passw0rd")
+
+		resp = c.sendReq(http.MethodPost, "/maze", `{"gridSize": "4x4", "entrance": "A1", "walls": ["B1", "B2", "A4", "B4", "C4"]}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		var maze IDResp
+		Expect(json.NewDecoder(resp.Body).Decode(&maze)).To(Succeed())
+		path := fmt.Sprintf("/maze/%d/print", maze.ID)
+
+		By("text by default")
+		resp = c.sendReq(http.MethodGet, path, "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Header.Get("Content-Type")).To(Equal("text/plain"))
+		Expect(io.ReadAll(resp.Body)).To(BeEquivalentTo("|_|X|_|_|\n|_|X|_|_|\n|_|_|_|_|\n|X|X|X|_|\n"))
+
+		By("text with a solution")
+		resp = c.sendReq(http.MethodGet, path+"?solution=min", "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Header.Get("Content-Type")).To(Equal("text/plain; charset=utf-8"))
+		Expect(io.ReadAll(resp.Body)).To(BeEquivalentTo("  ABCD\n1 E#..\n2 *#..\n3 ****\n4 ###O\n"))
+
+		By("text style")
+		resp = c.sendReq(http.MethodGet, path+"?style=unicode&labels=false", "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(io.ReadAll(resp.Body)).To(BeEquivalentTo("◉ ╷\n  ╵\n\n╶───╴ ◎\n"))
+
+		By("accept header")
+		resp = c.sendReqWithHeaders(http.MethodGet, path, "", map[string]string{"Accept": "image/svg+xml"})
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Header.Get("Content-Type")).To(Equal("image/svg+xml"))
+		Expect(io.ReadAll(resp.Body)).To(ContainSubstring("<svg"))
+
+		By("format parameter")
+		resp = c.sendReqWithHeaders(http.MethodGet, path+"?format=png&solution=max&cellSize=5&labels=false", "", map[string]string{"Accept": "image/svg+xml"})
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Header.Get("Content-Type")).To(Equal("image/png"))
+		img, err := png.Decode(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(img.Bounds().Dx()).To(Equal(20))
+
+		By("invalid parameters")
+		for _, query := range []string{"format=gif", "solution=some", "style=fancy", "cellSize=-1", "cellSize=101", "labels=maybe"} {
+			resp = c.sendReq(http.MethodGet, path+"?"+query, "")
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest), query)
+		}
+	})
+
+	Specify("Solution jobs", func() {
+		resp := c.sendReq(http.MethodPost, "/user", `{"username": "alex", "password": "passw0rd"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		c.login("alex", "passw0rd")
+
+		resp = c.sendReq(http.MethodPost, "/maze", `{"gridSize": "8x10", "entrance": "A1", "walls": ["C1", "G1", "A2", "A8", "B8", "C8", "D8", "E8", "F8", "G8", "H8", "I8"]}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		var maze IDResp
+		Expect(json.NewDecoder(resp.Body).Decode(&maze)).To(Succeed())
+
+		By("submit")
+		resp = c.sendReq(http.MethodPost, fmt.Sprintf("/maze/%d/solution-jobs?steps=min", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+		var job SolveJob
+		Expect(json.NewDecoder(resp.Body).Decode(&job)).To(Succeed())
+		Expect(job.ID).ToNot(BeEmpty())
+		Expect(job.MazeID).To(Equal(maze.ID))
+		Expect(resp.Header.Get("Location")).To(Equal("/solution-jobs/" + job.ID))
+
+		By("wait for the result")
+		Eventually(func() string {
+			resp = c.sendReq(http.MethodGet, "/solution-jobs/"+job.ID, "")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(json.NewDecoder(resp.Body).Decode(&job)).To(Succeed())
+			return job.Status
+		}, 5*time.Second, 10*time.Millisecond).Should(Equal("done"))
+		Expect(job.Path).To(Equal([]string{"A1", "B1", "B2", "B3", "B4", "B5", "B6", "B7", "C7", "D7", "E7", "F7", "G7", "H7", "I7", "J7", "J8"}))
+		Expect(job.Explored).To(BeNumerically(">", 0))
+		Expect(job.Optimal).ToNot(BeNil())
+		Expect(*job.Optimal).To(BeTrue())
+
+		By("best effort")
+		resp = c.sendReq(http.MethodPost, fmt.Sprintf("/maze/%d/solution-jobs?steps=max&bestEffort=true", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+		var bestEffortJob SolveJob
+		Expect(json.NewDecoder(resp.Body).Decode(&bestEffortJob)).To(Succeed())
+		Expect(bestEffortJob.BestEffort).To(BeTrue())
+		Expect(bestEffortJob.Optimal).To(BeNil())
+
+		By("cancel a finished job")
+		resp = c.sendReq(http.MethodDelete, "/solution-jobs/"+job.ID, "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(json.NewDecoder(resp.Body).Decode(&job)).To(Succeed())
+		Expect(job.Status).To(Equal("done"))
+
+		By("invalid requests")
+		resp = c.sendReq(http.MethodPost, fmt.Sprintf("/maze/%d/solution-jobs?steps=some", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		resp = c.sendReq(http.MethodPost, fmt.Sprintf("/maze/%d/solution-jobs?steps=min&bestEffort=true", maze.ID), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		resp = c.sendReq(http.MethodPost, fmt.Sprintf("/maze/%d/solution-jobs?steps=min", maze.ID+1), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+		resp = c.sendReq(http.MethodGet, "/solution-jobs/nosuchjob", "")
+		Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+
+		By("other user")
+		c2 := &client{server: server}
+		resp = c2.sendReq(http.MethodPost, "/user", `{"username": "alex2", "password": "passw0rd"}`)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		c2.login("alex2", "passw0rd")
+		resp = c2.sendReq(http.MethodGet, "/solution-jobs/"+job.ID, "")
+		Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+		resp = c2.sendReq(http.MethodDelete, "/solution-jobs/"+job.ID, "")
+		Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+	})
 })
 
 type Maze struct {
@@ -105,7 +987,85 @@ type Maze struct {
 }
 
 type Mazes struct {
-	Mazes []Maze
+	Mazes      []Maze
+	Total      int64
+	NextCursor string
+}
+
+type GeneratedMaze struct {
+	Maze
+	Algorithm  string
+	Seed       int64
+	Difficulty string
+}
+
+type MazeExits struct {
+	ExitMode string
+	Exits    []string
+}
+
+type Solution struct {
+	Path []string
+	Exit string
+	Cost int
+}
+
+type ShortestPaths struct {
+	Solution
+	Paths [][]string
+	Total int
+}
+
+type MazeStats struct {
+	ShortestPathLength int
+	ShortestPaths      int
+	SimplePaths        int
+}
+
+type ImportResult struct {
+	Imported int
+	Failed   int
+	Items    []struct {
+		Index int
+		ID    int64
+		Error string
+	}
+}
+
+type MazeAnalysis struct {
+	ReachableCells     int
+	UnreachableRegions int
+	UnreachableCells   int
+	DeadEnds           int
+	BranchingFactor    float64
+	Chokepoints        []string
+	ShortestPathLength int
+	LongestPathLength  int
+	LongestPathOptimal bool
+	Difficulty         int
+}
+
+type SolveJob struct {
+	ID         string
+	MazeID     int64
+	BestEffort bool
+	Status     string
+	Explored   int64
+	Path       []string
+	Optimal    *bool
+}
+
+type SolutionInfo struct {
+	Solution
+	Optimal   bool
+	ElapsedMs float64
+	Explored  int64
+}
+
+type DirectionsSolution struct {
+	SolutionInfo
+	Directions string
+	Compass    string
 }
 
 type IDResp struct {
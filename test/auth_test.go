@@ -32,7 +32,14 @@ var _ = Describe("Auth", func() {
 			{http.MethodPost, "/maze", http.StatusUnauthorized},
 			{http.MethodGet, "/maze", http.StatusUnauthorized},
 			{http.MethodGet, "/maze/1", http.StatusUnauthorized},
+			{http.MethodPost, "/maze/generate", http.StatusUnauthorized},
+			{http.MethodPut, "/maze/1", http.StatusUnauthorized},
+			{http.MethodPatch, "/maze/1", http.StatusUnauthorized},
+			{http.MethodDelete, "/maze/1", http.StatusUnauthorized},
 			{http.MethodGet, "/maze/1/solution", http.StatusUnauthorized},
+			{http.MethodPost, "/maze/1/solution-jobs", http.StatusUnauthorized},
+			{http.MethodGet, "/solution-jobs/1", http.StatusUnauthorized},
+			{http.MethodDelete, "/solution-jobs/1", http.StatusUnauthorized},
 		}
 		for _, tc := range testCases {
 			By(tc.method + " " + tc.path)
@@ -96,10 +103,18 @@ func (c *client) login(email, password string) {
 }
 
 func (c *client) sendReq(method, path string, body string) *http.Response {
+	return c.sendReqWithHeaders(method, path, body, nil)
+}
+
+// sendReqWithHeaders sends a JSON request by default, headers override the defaults.
+func (c *client) sendReqWithHeaders(method, path string, body string, headers map[string]string) *http.Response {
 	req, err := http.NewRequest(method, c.server.URL+path, strings.NewReader(body))
 	Expect(err).ToNot(HaveOccurred())
 	req.Header.Set("Authorization", "Bearer "+c.token)
 	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
 	resp, err := http.DefaultClient.Do(req)
 	Expect(err).ToNot(HaveOccurred())
 	return resp